@@ -1,13 +1,22 @@
 package auth
 
 import (
+	"bufio"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/pkg/browser"
 	"golang.org/x/oauth2"
@@ -18,19 +27,46 @@ import (
 	"oredavids.com/myCal/internal/config"
 )
 
+// authTimeout bounds how long we wait for the user to finish authorizing
+const authTimeout = 5 * time.Minute
+
+// ErrAccessDenied is returned when the user declines the consent screen
+var ErrAccessDenied = errors.New("authorization was denied")
+
+// Options controls how the user is authorized when no token is cached
+type Options struct {
+	// NoBrowser prints the authorization URL and reads the redirect URL (or
+	// code) from stdin instead of opening a browser and listening on localhost.
+	// Useful over SSH where the browser runs on a different machine.
+	NoBrowser bool
+}
+
 // GetCalendarService creates and returns an authenticated calendar service
-func GetCalendarService(ctx context.Context) (*calendar.Service, error) {
+func GetCalendarService(ctx context.Context, opts Options) (*calendar.Service, error) {
 	b, err := os.ReadFile(config.GetCredentialsPath())
 	if err != nil {
 		return nil, fmt.Errorf("unable to read client secret file: %v", err)
 	}
 
-	oauthConfig, err := google.ConfigFromJSON(b, calendar.CalendarReadonlyScope)
+	mode, err := resolveAuthMode(b)
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse client secret file to config: %v", err)
+		return nil, err
 	}
 
-	client := getClient(oauthConfig)
+	var client *http.Client
+	if mode == config.AuthModeServiceAccount {
+		client, err = serviceAccountClient(ctx, b, config.Get().ImpersonateUser)
+	} else {
+		var oauthConfig *oauth2.Config
+		oauthConfig, err = google.ConfigFromJSON(b, calendar.CalendarReadonlyScope)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse client secret file to config: %v", err)
+		}
+		client, err = getClient(ctx, oauthConfig, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	srv, err := calendar.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
@@ -41,83 +77,242 @@ func GetCalendarService(ctx context.Context) (*calendar.Service, error) {
 }
 
 // getClient retrieves a token, saves the token, then returns the generated client
-func getClient(oauthConfig *oauth2.Config) *http.Client {
+func getClient(ctx context.Context, oauthConfig *oauth2.Config, opts Options) (*http.Client, error) {
 	tokFile := config.GetTokenPath()
 	tok, err := tokenFromFile(tokFile)
 	if err != nil {
 		fmt.Println("Token required...")
-		tok = getTokenFromWeb(oauthConfig)
-		saveToken(tokFile, tok)
+		if opts.NoBrowser {
+			tok, err = getTokenFromPaste(ctx, oauthConfig)
+		} else {
+			tok, err = getTokenFromWeb(ctx, oauthConfig)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := saveToken(tokFile, tok); err != nil {
+			return nil, err
+		}
 	}
-	return oauthConfig.Client(context.Background(), tok)
+	return oauthConfig.Client(context.Background(), tok), nil
+}
+
+// callbackResult is what the local callback server hands back to the flow
+type callbackResult struct {
+	code string
+	err  error
 }
 
 // getTokenFromWeb requests a token from the web, then returns the retrieved token
-func getTokenFromWeb(oauthConfig *oauth2.Config) *oauth2.Token {
-	// Channel to receive the auth code
-	codeChan := make(chan string)
+func getTokenFromWeb(ctx context.Context, oauthConfig *oauth2.Config) (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(ctx, authTimeout)
+	defer cancel()
 
-	// Find an available port
+	state, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomString(64)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find an available loopback port
 	listener, err := findAvailablePort()
 	if err != nil {
-		log.Fatalf("Unable to find available port: %v", err)
+		return nil, fmt.Errorf("unable to find available port: %v", err)
 	}
 	port := listener.Addr().(*net.TCPAddr).Port
 
 	// Update the redirect URL to use the actual port
-	oauthConfig.RedirectURL = fmt.Sprintf("http://localhost:%d", port)
+	oauthConfig.RedirectURL = fmt.Sprintf("http://127.0.0.1:%d", port)
+
+	// Buffered so a late or repeated callback never blocks the handler
+	resultChan := make(chan callbackResult, 1)
+	deliver := func(r callbackResult) {
+		select {
+		case resultChan <- r:
+		default:
+		}
+	}
 
 	// Set up handler
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		code := r.URL.Query().Get("code")
-		if code != "" {
-			w.Header().Set("Content-Type", "text/html")
-			fmt.Fprintf(w, "<html><body><h1>Authorization successful!</h1><p>You can close this window.</p></body></html>")
-			codeChan <- code
-		} else {
-			fmt.Fprintf(w, "No code received")
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			writeCallbackPage(w, http.StatusBadRequest, "Authorization failed", "The request did not come from this login attempt. Please try again from myCal.")
+			return
+		}
+
+		if errCode := query.Get("error"); errCode != "" {
+			if errCode == "access_denied" {
+				writeCallbackPage(w, http.StatusForbidden, "Authorization denied", "myCal was not given access to your calendar. You can close this window.")
+				deliver(callbackResult{err: ErrAccessDenied})
+			} else {
+				writeCallbackPage(w, http.StatusBadRequest, "Authorization failed", "Google returned: "+errCode)
+				deliver(callbackResult{err: fmt.Errorf("authorization failed: %s", errCode)})
+			}
+			return
+		}
+
+		code := query.Get("code")
+		if code == "" {
+			writeCallbackPage(w, http.StatusBadRequest, "Authorization failed", "No code received")
+			return
 		}
+		writeCallbackPage(w, http.StatusOK, "Authorization successful!", "You can close this window.")
+		deliver(callbackResult{code: code})
 	})
 
-	server := &http.Server{Handler: mux}
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
 	go func() {
 		if err := server.Serve(listener); err != http.ErrServerClosed {
 			log.Printf("HTTP server error: %v", err)
 		}
 	}()
+	defer server.Shutdown(context.Background())
 
 	// Open browser for authorization
-	authURL := oauthConfig.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
+	authURL := oauthConfig.AuthCodeURL(state, authCodeOptions(verifier)...)
 	fmt.Printf("Opening browser for authorization (callback on port %d)...\n", port)
-	browser.OpenURL(authURL)
+	if err := browser.OpenURL(authURL); err != nil {
+		fmt.Printf("Unable to open a browser, visit this URL instead:\n\n%s\n\n", authURL)
+	}
 
-	// Wait for the auth code
-	authCode := <-codeChan
+	// Wait for the auth code, the timeout or cancellation
+	var result callbackResult
+	select {
+	case result = <-resultChan:
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s waiting for authorization", authTimeout)
+		}
+		return nil, ctx.Err()
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
 
-	// Shutdown the server
-	server.Shutdown(context.Background())
+	tok, err := oauthConfig.Exchange(ctx, result.code, pkceVerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve token from web: %v", err)
+	}
+	return tok, nil
+}
+
+// getTokenFromPaste prints the authorization URL and reads the redirect URL or
+// code pasted back by the user, so no local browser or callback server is needed
+func getTokenFromPaste(ctx context.Context, oauthConfig *oauth2.Config) (*oauth2.Token, error) {
+	state, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomString(64)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing listens here; the browser's failed redirect carries the code
+	oauthConfig.RedirectURL = "http://localhost"
 
-	tok, err := oauthConfig.Exchange(context.TODO(), authCode)
+	authURL := oauthConfig.AuthCodeURL(state, authCodeOptions(verifier)...)
+	fmt.Printf("Open this URL in a browser on any machine:\n\n%s\n\n", authURL)
+	fmt.Println("After approving, the browser is redirected to a localhost page that fails to load.")
+	fmt.Print("Paste the full URL from the address bar (or just the code): ")
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("unable to read authorization code: %v", err)
+	}
+
+	authCode, err := parseAuthCode(line, state)
 	if err != nil {
-		log.Fatalf("Unable to retrieve token from web: %v", err)
+		return nil, err
+	}
+
+	tok, err := oauthConfig.Exchange(ctx, authCode, pkceVerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve token from web: %v", err)
+	}
+	return tok, nil
+}
+
+// parseAuthCode extracts the code from a pasted redirect URL, or returns the
+// input itself when it is already a bare code. A pasted URL must carry the
+// state we generated.
+func parseAuthCode(input string, state string) (string, error) {
+	input = strings.TrimSpace(input)
+	u, err := url.Parse(input)
+	if err != nil || u.Scheme == "" {
+		if input == "" {
+			return "", errors.New("no authorization code found in input")
+		}
+		return input, nil
+	}
+
+	query := u.Query()
+	if query.Get("error") == "access_denied" {
+		return "", ErrAccessDenied
+	}
+	if query.Get("state") != state {
+		return "", errors.New("state mismatch in redirect URL; please start the login again")
+	}
+	if code := query.Get("code"); code != "" {
+		return code, nil
+	}
+	return "", errors.New("no authorization code found in redirect URL")
+}
+
+// writeCallbackPage renders a minimal HTML page for the browser
+func writeCallbackPage(w http.ResponseWriter, status int, title string, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<html><body><h1>%s</h1><p>%s</p></body></html>", html.EscapeString(title), html.EscapeString(message))
+}
+
+// authCodeOptions returns the parameters for the authorization URL. Consent is
+// always prompted so Google issues a refresh token even for a returning user.
+func authCodeOptions(verifier string) []oauth2.AuthCodeOption {
+	return append(pkceChallengeOptions(verifier), oauth2.AccessTypeOffline, oauth2.ApprovalForce)
+}
+
+// pkceChallengeOptions returns the S256 code challenge parameters for a verifier
+func pkceChallengeOptions(verifier string) []oauth2.AuthCodeOption {
+	sum := sha256.Sum256([]byte(verifier))
+	return []oauth2.AuthCodeOption{
+		oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(sum[:])),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	}
+}
+
+// pkceVerifierOption returns the code verifier parameter for the token exchange
+func pkceVerifierOption(verifier string) oauth2.AuthCodeOption {
+	return oauth2.SetAuthURLParam("code_verifier", verifier)
+}
+
+// randomString returns a URL-safe random string built from n random bytes
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to generate random value: %v", err)
 	}
-	return tok
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
-// findAvailablePort tries preferred ports first, then falls back to any available port
+// findAvailablePort tries preferred loopback ports first, then falls back to any available port
 func findAvailablePort() (net.Listener, error) {
 	preferredPorts := []int{3000, 3001, 8080, 8000, 9000}
 
 	for _, port := range preferredPorts {
-		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
 		if err == nil {
 			return listener, nil
 		}
 	}
 
 	// Fall back to any available port
-	return net.Listen("tcp", ":0")
+	return net.Listen("tcp", "127.0.0.1:0")
 }
 
 // tokenFromFile retrieves a token from a local file
@@ -133,12 +328,12 @@ func tokenFromFile(file string) (*oauth2.Token, error) {
 }
 
 // saveToken saves a token to a file path
-func saveToken(path string, token *oauth2.Token) {
+func saveToken(path string, token *oauth2.Token) error {
 	fmt.Printf("Saving credential file to: %s\n", path)
 	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
-		log.Fatalf("Unable to cache oauth token: %v", err)
+		return fmt.Errorf("unable to cache oauth token: %v", err)
 	}
 	defer f.Close()
-	json.NewEncoder(f).Encode(token)
+	return json.NewEncoder(f).Encode(token)
 }
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAuthCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"redirect URL", "http://localhost/?state=s1&code=4/abc&scope=x", "4/abc", false},
+		{"surrounding spaces", "  http://localhost:8080/callback?code=xyz&state=s1 \n", "xyz", false},
+		{"bare code", "4/0AbCdEf", "4/0AbCdEf", false},
+		{"empty", "   ", "", true},
+		{"state mismatch", "http://localhost/?state=other&code=abc", "", true},
+		{"missing state", "http://localhost/?code=abc", "", true},
+		{"no code", "http://localhost/?state=s1", "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseAuthCode(tt.input, "s1")
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseAuthCode(%q) = %q, %v", tt.name, tt.input, got, err)
+		}
+	}
+
+	if _, err := parseAuthCode("http://localhost/?error=access_denied&state=s1", "s1"); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("parseAuthCode(access_denied) error = %v, want ErrAccessDenied", err)
+	}
+}
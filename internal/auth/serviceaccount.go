@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/calendar/v3"
+
+	"oredavids.com/myCal/internal/config"
+)
+
+// credentialsFileType returns the kind of credentials stored in a client
+// secret file: "service_account" for service-account keys, "installed" or
+// "web" for OAuth clients
+func credentialsFileType(b []byte) (string, error) {
+	var f struct {
+		Type      string          `json:"type"`
+		Installed json.RawMessage `json:"installed"`
+		Web       json.RawMessage `json:"web"`
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return "", fmt.Errorf("unable to parse credentials file: %v", err)
+	}
+
+	switch {
+	case f.Type != "":
+		return f.Type, nil
+	case f.Installed != nil:
+		return "installed", nil
+	case f.Web != nil:
+		return "web", nil
+	}
+	return "", fmt.Errorf("unrecognized credentials file format")
+}
+
+// resolveAuthMode picks the auth mode from the config, detecting it from the
+// credentials file when set to auto
+func resolveAuthMode(b []byte) (string, error) {
+	mode := config.Get().AuthMode
+	if mode != config.AuthModeAuto && mode != "" {
+		return mode, nil
+	}
+
+	fileType, err := credentialsFileType(b)
+	if err != nil {
+		return "", err
+	}
+	if fileType == "service_account" {
+		return config.AuthModeServiceAccount, nil
+	}
+	return config.AuthModeOAuth, nil
+}
+
+// serviceAccountClient returns a client authorized with a service-account key,
+// impersonating subject through domain-wide delegation when it is set
+func serviceAccountClient(ctx context.Context, b []byte, subject string) (*http.Client, error) {
+	jwtConfig, err := google.JWTConfigFromJSON(b, calendar.CalendarReadonlyScope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse service account key: %v", err)
+	}
+	jwtConfig.Subject = subject
+	return jwtConfig.Client(ctx), nil
+}
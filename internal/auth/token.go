@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+
+	"oredavids.com/myCal/internal/config"
+)
+
+const (
+	tokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+	revokeURL    = "https://oauth2.googleapis.com/revoke"
+	scopePrefix  = "https://www.googleapis.com/auth/"
+)
+
+// ErrNotLoggedIn is returned when no OAuth token is cached
+var ErrNotLoggedIn = errors.New("not logged in")
+
+// TokenStatus describes the credentials myCal is currently using
+type TokenStatus struct {
+	Mode            string
+	Email           string
+	Impersonating   string
+	Scopes          []string
+	Expiry          time.Time
+	HasRefreshToken bool
+	TokenPath       string
+	CredentialsPath string
+}
+
+// Status reports the account, scopes, expiry and storage location of the
+// current credentials. OAuth tokens are refreshed if needed to query them.
+func Status(ctx context.Context) (*TokenStatus, error) {
+	status := &TokenStatus{CredentialsPath: config.GetCredentialsPath()}
+
+	b, err := os.ReadFile(status.CredentialsPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read client secret file: %v", err)
+	}
+	status.Mode, err = resolveAuthMode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if status.Mode == config.AuthModeServiceAccount {
+		var key struct {
+			ClientEmail string `json:"client_email"`
+		}
+		if err := json.Unmarshal(b, &key); err != nil {
+			return nil, fmt.Errorf("unable to parse service account key: %v", err)
+		}
+		status.Email = key.ClientEmail
+		status.Impersonating = config.Get().ImpersonateUser
+		status.Scopes = []string{calendar.CalendarReadonlyScope}
+		return status, nil
+	}
+
+	status.TokenPath = config.GetTokenPath()
+	tok, err := tokenFromFile(status.TokenPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return status, ErrNotLoggedIn
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token file: %v", err)
+	}
+
+	oauthConfig, err := google.ConfigFromJSON(b, calendar.CalendarReadonlyScope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse client secret file to config: %v", err)
+	}
+
+	fresh, err := oauthConfig.TokenSource(ctx, tok).Token()
+	if err != nil {
+		return nil, fmt.Errorf("unable to refresh token: %v", err)
+	}
+	status.Expiry = fresh.Expiry
+	status.HasRefreshToken = fresh.RefreshToken != ""
+
+	status.Scopes, err = tokenScopes(ctx, fresh.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// The primary calendar's ID is the account's email address, which avoids
+	// asking for a separate userinfo scope
+	srv, err := calendar.NewService(ctx, option.WithHTTPClient(oauthConfig.Client(ctx, fresh)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve Calendar client: %v", err)
+	}
+	if primary, err := srv.Calendars.Get("primary").Context(ctx).Do(); err == nil {
+		status.Email = primary.Id
+	}
+
+	return status, nil
+}
+
+// Login runs the authorization flow even if a token is cached, requesting
+// extraScopes on top of read-only calendar access, and saves the new token
+func Login(ctx context.Context, opts Options, extraScopes []string) error {
+	b, err := os.ReadFile(config.GetCredentialsPath())
+	if err != nil {
+		return fmt.Errorf("unable to read client secret file: %v", err)
+	}
+	mode, err := resolveAuthMode(b)
+	if err != nil {
+		return err
+	}
+	if mode == config.AuthModeServiceAccount {
+		return errors.New("service accounts do not log in; remove the key or set authMode to oauth")
+	}
+
+	scopes := []string{calendar.CalendarReadonlyScope}
+	for _, scope := range extraScopes {
+		scopes = append(scopes, ExpandScope(scope))
+	}
+
+	oauthConfig, err := google.ConfigFromJSON(b, scopes...)
+	if err != nil {
+		return fmt.Errorf("unable to parse client secret file to config: %v", err)
+	}
+
+	var tok *oauth2.Token
+	if opts.NoBrowser {
+		tok, err = getTokenFromPaste(ctx, oauthConfig)
+	} else {
+		tok, err = getTokenFromWeb(ctx, oauthConfig)
+	}
+	if err != nil {
+		return err
+	}
+	return saveToken(config.GetTokenPath(), tok)
+}
+
+// Logout deletes the cached token
+func Logout() error {
+	err := os.Remove(config.GetTokenPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotLoggedIn
+	}
+	return err
+}
+
+// Revoke asks Google to revoke the cached token's grant, then deletes it
+func Revoke(ctx context.Context) error {
+	tok, err := tokenFromFile(config.GetTokenPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotLoggedIn
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read token file: %v", err)
+	}
+
+	// Revoking the refresh token also revokes every access token issued from it
+	token := tok.RefreshToken
+	if token == "" {
+		token = tok.AccessToken
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, revokeURL,
+		strings.NewReader(url.Values{"token": {token}}.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to revoke token: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// A 400 means the grant is already invalid, which is what we wanted anyway
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
+		return fmt.Errorf("unable to revoke token: %s", resp.Status)
+	}
+
+	return Logout()
+}
+
+// ExpandScope turns a short scope name like "calendar.events" into its full URL
+func ExpandScope(scope string) string {
+	if strings.HasPrefix(scope, "https://") {
+		return scope
+	}
+	return scopePrefix + scope
+}
+
+// tokenScopes asks the tokeninfo endpoint which scopes an access token carries
+func tokenScopes(ctx context.Context, accessToken string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		tokenInfoURL+"?"+url.Values{"access_token": {accessToken}}.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query token info: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to query token info: %s", resp.Status)
+	}
+
+	var info struct {
+		Scope string `json:"scope"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("unable to parse token info: %v", err)
+	}
+	return strings.Fields(info.Scope), nil
+}
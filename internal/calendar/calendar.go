@@ -1,7 +1,10 @@
 package calendar
 
 import (
-	"strings"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
 	"time"
 
 	"google.golang.org/api/calendar/v3"
@@ -10,101 +13,228 @@ import (
 // Event wraps a calendar event with additional computed fields
 type Event struct {
 	*calendar.Event
+	CalendarID string
 	StartTime  time.Time
+	EndTime    time.Time
 	IsAllDay   bool
 	MeetingURL string
+
+	// MeetingProvider names the service hosting MeetingURL, e.g. "Zoom"; it
+	// is empty for links from unknown services
+	MeetingProvider string
+
+	// MeetingCode and Passcode identify the meeting to the provider, and
+	// DialIns list phone numbers for joining it; all are optional
+	MeetingCode string
+	Passcode    string
+	DialIns     []DialIn
+
+	// Hidden is set when the configured filter hides the event
+	Hidden bool
+
+	// Conflicts lists the other fetched events that overlap this one
+	Conflicts []*Event
+}
+
+// calendarIDs lists the calendars events are read from
+var calendarIDs = []string{"primary"}
+
+// lookahead is how far ahead the next and upcoming events are looked for
+var lookahead = 30 * 24 * time.Hour
+
+// maxPages is how many pages of a calendar's events are read while looking
+// for enough visible ones, so calendars whose events are mostly hidden don't
+// have every page fetched on each refresh
+const maxPages = 10
+
+// SetLookahead changes how many days ahead the next and upcoming events are
+// looked for
+func SetLookahead(days int) {
+	lookahead = time.Duration(days) * 24 * time.Hour
+}
+
+// SetCalendars changes which calendars are read; an empty list means the primary calendar
+func SetCalendars(ids []string) {
+	if len(ids) == 0 {
+		calendarIDs = []string{"primary"}
+		return
+	}
+	calendarIDs = ids
 }
 
-// FetchTodayEvents retrieves events for the rest of today
+// FetchTodayEvents retrieves events for the rest of today, including those
+// still in progress
 func FetchTodayEvents(srv *calendar.Service) ([]*Event, error) {
 	now := time.Now()
-	timeToStart := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location()).Format(time.RFC3339)
 	endOfDay := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location()).Format(time.RFC3339)
 
-	events, err := srv.Events.List("primary").ShowDeleted(false).
-		SingleEvents(true).TimeMin(timeToStart).TimeMax(endOfDay).OrderBy("startTime").Do()
-	if err != nil {
-		return nil, err
-	}
-
-	return wrapEvents(events.Items), nil
+	// TimeMin bounds the end time, so this includes meetings still in progress
+	return listEvents(srv, func(call *calendar.EventsListCall) *calendar.EventsListCall {
+		return call.TimeMin(now.Format(time.RFC3339)).TimeMax(endOfDay)
+	}, nil)
 }
 
-// FetchUpcomingEvents retrieves the next N events
+// FetchUpcomingEvents retrieves the next N visible events within the
+// lookahead, along with any hidden events among them
 func FetchUpcomingEvents(srv *calendar.Service, maxResults int64, excludeToday bool) ([]*Event, error) {
 	now := time.Now()
 
-	var timeToStart string
+	start := now
 	if excludeToday {
-		timeToStart = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location()).Format(time.RFC3339)
-	} else {
-		timeToStart = now.Format(time.RFC3339)
+		start = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
 	}
 
-	events, err := srv.Events.List("primary").ShowDeleted(false).
-		SingleEvents(true).TimeMin(timeToStart).MaxResults(maxResults).OrderBy("startTime").Do()
+	// Multi-day events that began today are already among today's events
+	counts := func(e *Event) bool {
+		return !e.Hidden && !e.StartTime.Before(start)
+	}
+	events, err := listEvents(srv, func(call *calendar.EventsListCall) *calendar.EventsListCall {
+		return call.TimeMin(start.Format(time.RFC3339)).TimeMax(now.Add(lookahead).Format(time.RFC3339)).MaxResults(maxResults)
+	}, atLeast(int(maxResults), counts))
 	if err != nil {
 		return nil, err
 	}
 
-	return wrapEvents(events.Items), nil
+	if excludeToday {
+		events = slices.DeleteFunc(events, func(e *Event) bool {
+			return e.StartTime.Before(start)
+		})
+	}
+
+	// Keep maxResults visible events, along with the hidden ones among them
+	visible := int64(0)
+	for i, event := range events {
+		if visible == maxResults {
+			return events[:i], nil
+		}
+		if !event.Hidden {
+			visible++
+		}
+	}
+	return events, nil
+}
+
+// FetchEventsBetween retrieves all events that overlap [from, to), including
+// those that started before from and are still going on
+func FetchEventsBetween(srv *calendar.Service, from time.Time, to time.Time) ([]*Event, error) {
+	return listEvents(srv, func(call *calendar.EventsListCall) *calendar.EventsListCall {
+		return call.TimeMin(from.Format(time.RFC3339)).TimeMax(to.Format(time.RFC3339))
+	}, nil)
 }
 
-// FetchNextEvent retrieves the next upcoming event (timed, not all-day)
+// FetchNextEvent retrieves the next upcoming event within the lookahead
+// (timed, not all-day and not hidden)
 func FetchNextEvent(srv *calendar.Service) (*Event, error) {
 	now := time.Now()
 	timeMin := now.Format(time.RFC3339)
 
-	events, err := srv.Events.List("primary").ShowDeleted(false).
-		SingleEvents(true).TimeMin(timeMin).MaxResults(5).OrderBy("startTime").Do()
+	isNext := func(e *Event) bool {
+		return !e.IsAllDay && !e.Hidden && e.StartTime.After(now)
+	}
+	events, err := listEvents(srv, func(call *calendar.EventsListCall) *calendar.EventsListCall {
+		return call.TimeMin(timeMin).TimeMax(now.Add(lookahead).Format(time.RFC3339)).MaxResults(5)
+	}, atLeast(1, isNext))
 	if err != nil {
 		return nil, err
 	}
 
 	// Find first timed event (not all-day)
-	for _, e := range events.Items {
-		if e.Start.DateTime != "" {
-			wrapped := wrapEvent(e)
-			if wrapped.StartTime.After(now) {
-				return wrapped, nil
-			}
+	for _, e := range events {
+		if isNext(e) {
+			return e, nil
 		}
 	}
 
 	return nil, nil
 }
 
-// wrapEvents converts calendar events to our Event type
-func wrapEvents(items []*calendar.Event) []*Event {
-	events := make([]*Event, 0, len(items))
-	for _, item := range items {
-		events = append(events, wrapEvent(item))
+// atLeast returns a check for listEvents that stops paging once n of a
+// calendar's events match
+func atLeast(n int, match func(*Event) bool) func([]*Event) bool {
+	return func(events []*Event) bool {
+		count := 0
+		for _, e := range events {
+			if match(e) {
+				count++
+			}
+		}
+		return count >= n
 	}
-	return events
+}
+
+// listEvents runs the same query against every configured calendar and merges
+// the results in start order. An event shared by several calendars (e.g. a
+// meeting and its room) is only returned once. Each calendar's results are
+// paged through to the end when enough is nil, or otherwise until enough
+// reports it has enough events or maxPages pages were read.
+func listEvents(srv *calendar.Service, query func(*calendar.EventsListCall) *calendar.EventsListCall, enough func([]*Event) bool) ([]*Event, error) {
+	var events []*Event
+	seen := make(map[string]bool)
+
+	for _, id := range calendarIDs {
+		call := query(srv.Events.List(id).ShowDeleted(false).SingleEvents(true).OrderBy("startTime"))
+
+		var calendarEvents []*Event
+		for page := 1; ; page++ {
+			result, err := call.Do()
+			if err != nil {
+				return nil, fmt.Errorf("calendar %s: %w", id, err)
+			}
+			for _, item := range result.Items {
+				event := wrapEvent(item)
+				event.CalendarID = id
+				event.Hidden = filter.hides(event)
+				calendarEvents = append(calendarEvents, event)
+			}
+			if result.NextPageToken == "" || (enough != nil && (enough(calendarEvents) || page == maxPages)) {
+				break
+			}
+			call.PageToken(result.NextPageToken)
+		}
+
+		for _, event := range calendarEvents {
+			key := event.ICalUID + "|" + event.StartTime.String()
+			if event.ICalUID != "" && seen[key] {
+				continue
+			}
+			seen[key] = true
+			events = append(events, event)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	markConflicts(events)
+	return events, nil
 }
 
 // wrapEvent converts a single calendar event to our Event type
 func wrapEvent(e *calendar.Event) *Event {
 	event := &Event{Event: e}
 
-	// Parse start time
+	// Parse start and end time
 	if e.Start.DateTime != "" {
 		event.StartTime, _ = time.Parse(time.RFC3339, e.Start.DateTime)
+		event.StartTime = event.StartTime.In(time.Local)
 		event.IsAllDay = false
 	} else {
-		event.StartTime, _ = time.Parse("2006-01-02", e.Start.Date)
+		event.StartTime, _ = time.ParseInLocation("2006-01-02", e.Start.Date, time.Local)
 		event.IsAllDay = true
 	}
-
-	// Find meeting URL
-	if e.ConferenceData != nil && len(e.ConferenceData.EntryPoints) > 0 {
-		event.MeetingURL = e.ConferenceData.EntryPoints[0].Uri
-	} else if e.HangoutLink != "" {
-		event.MeetingURL = e.HangoutLink
-	} else if e.Location != "" && strings.HasPrefix(e.Location, "http") {
-		event.MeetingURL = e.Location
+	if e.End != nil && e.End.DateTime != "" {
+		event.EndTime, _ = time.Parse(time.RFC3339, e.End.DateTime)
+		event.EndTime = event.EndTime.In(time.Local)
+	} else if e.End != nil && e.End.Date != "" {
+		// All-day events end on the day after their last day
+		event.EndTime, _ = time.ParseInLocation("2006-01-02", e.End.Date, time.Local)
+	} else {
+		event.EndTime = event.StartTime
 	}
 
+	event.MeetingURL, event.MeetingProvider = findMeeting(e)
+	event.MeetingCode, event.Passcode, event.DialIns = meetingDetails(e)
+
 	return event
 }
 
@@ -113,6 +243,83 @@ func (e *Event) TimeUntilStart() time.Duration {
 	return e.StartTime.Sub(time.Now())
 }
 
+// OrganizerZone returns the time zone the event was scheduled in when its
+// clock differs from ours at the event's start, or nil
+func (e *Event) OrganizerZone() *time.Location {
+	if e.IsAllDay || e.Start == nil || e.Start.TimeZone == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(e.Start.TimeZone)
+	if err != nil {
+		return nil
+	}
+	_, theirs := e.StartTime.In(loc).Zone()
+	_, ours := e.StartTime.In(time.Local).Zone()
+	if theirs == ours {
+		return nil
+	}
+	return loc
+}
+
+// Overlaps reports whether the event takes place at any time in [from, to)
+func (e *Event) Overlaps(from time.Time, to time.Time) bool {
+	return e.StartTime.Before(to) && e.lastMoment().After(from.Add(-time.Nanosecond))
+}
+
+// DaySpan returns which day of the event day is (counting from 1) and how
+// many days the event covers, e.g. 2 and 4 on the second day of a
+// four-day conference
+func (e *Event) DaySpan(day time.Time) (int, int) {
+	first := startOfDay(e.StartTime)
+	total := daysBetween(first, startOfDay(e.lastMoment())) + 1
+	return daysBetween(first, startOfDay(day)) + 1, total
+}
+
+// lastMoment returns the last instant the event covers; EndTime itself is
+// exclusive
+func (e *Event) lastMoment() time.Time {
+	if !e.EndTime.After(e.StartTime) {
+		return e.StartTime
+	}
+	return e.EndTime.Add(-time.Nanosecond)
+}
+
+// startOfDay returns local midnight on t's day
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// daysBetween returns the number of calendar days from one midnight to another
+func daysBetween(from time.Time, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+// Duration returns how long the event lasts
+func (e *Event) Duration() time.Duration {
+	return e.EndTime.Sub(e.StartTime)
+}
+
+// InProgress reports whether the event has started but not yet ended
+func (e *Event) InProgress() bool {
+	now := time.Now()
+	return !now.Before(e.StartTime) && now.Before(e.EndTime)
+}
+
+// HasEnded reports whether the event is over
+func (e *Event) HasEnded() bool {
+	return !time.Now().Before(e.EndTime)
+}
+
+// Progress returns the fraction of the event that has elapsed, from 0 to 1
+func (e *Event) Progress() float64 {
+	if e.Duration() <= 0 {
+		return 1
+	}
+	elapsed := float64(time.Since(e.StartTime)) / float64(e.Duration())
+	return min(max(elapsed, 0), 1)
+}
+
 // GetDemoEvents returns mock events for demo/screenshot purposes
 func GetDemoEvents() ([]*Event, []*Event, *Event) {
 	now := time.Now()
@@ -124,9 +331,12 @@ func GetDemoEvents() ([]*Event, []*Event, *Event) {
 				Summary:  "Team Standup",
 				HtmlLink: "https://calendar.google.com/event/123",
 			},
-			StartTime:  time.Date(now.Year(), now.Month(), now.Day()+1, 10, 30, 0, 0, now.Location()),
-			IsAllDay:   false,
-			MeetingURL: "https://meet.google.com/abc-defg-hij",
+			StartTime:       time.Date(now.Year(), now.Month(), now.Day()+1, 10, 30, 0, 0, now.Location()),
+			EndTime:         time.Date(now.Year(), now.Month(), now.Day()+1, 10, 45, 0, 0, now.Location()),
+			IsAllDay:        false,
+			MeetingURL:      "https://meet.google.com/abc-defg-hij",
+			MeetingProvider: ProviderMeet,
+			DialIns:         []DialIn{{Number: "+1 608-555-0123", Region: "US", PIN: "123 456 789#"}},
 		},
 		{
 			Event: &calendar.Event{
@@ -134,6 +344,7 @@ func GetDemoEvents() ([]*Event, []*Event, *Event) {
 				HtmlLink: "https://calendar.google.com/event/456",
 			},
 			StartTime: time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, now.Location()),
+			EndTime:   time.Date(now.Year(), now.Month(), now.Day()+3, 0, 0, 0, 0, now.Location()),
 			IsAllDay:  true,
 		},
 		{
@@ -141,21 +352,46 @@ func GetDemoEvents() ([]*Event, []*Event, *Event) {
 				Summary:  "All Hands",
 				HtmlLink: "https://calendar.google.com/event/789",
 			},
-			StartTime:  time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, now.Location()),
-			IsAllDay:   false,
-			MeetingURL: "https://meet.google.com/xyz-uvwx-yz",
+			StartTime:       time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, now.Location()),
+			EndTime:         time.Date(now.Year(), now.Month(), now.Day()+2, 11, 0, 0, 0, now.Location()),
+			IsAllDay:        false,
+			MeetingURL:      "https://meet.google.com/xyz-uvwx-yz",
+			MeetingProvider: ProviderMeet,
+		},
+		{
+			Event: &calendar.Event{
+				Summary:  "Design Review",
+				HtmlLink: "https://calendar.google.com/event/246",
+				Attendees: []*calendar.EventAttendee{
+					{Email: "you@example.com", Self: true, ResponseStatus: "accepted"},
+					{Email: "ana@example.com", ResponseStatus: "accepted"},
+				},
+			},
+			StartTime: time.Date(now.Year(), now.Month(), now.Day()+2, 10, 30, 0, 0, now.Location()),
+			EndTime:   time.Date(now.Year(), now.Month(), now.Day()+2, 11, 15, 0, 0, now.Location()),
+			IsAllDay:  false,
 		},
 		{
 			Event: &calendar.Event{
 				Summary:  "1:1 Meeting",
 				HtmlLink: "https://calendar.google.com/event/101",
 			},
-			StartTime:  time.Date(now.Year(), now.Month(), now.Day()+3, 14, 30, 0, 0, now.Location()),
-			IsAllDay:   false,
-			MeetingURL: "https://meet.google.com/one-two-three",
+			StartTime:       time.Date(now.Year(), now.Month(), now.Day()+3, 14, 30, 0, 0, now.Location()),
+			EndTime:         time.Date(now.Year(), now.Month(), now.Day()+3, 15, 0, 0, 0, now.Location()),
+			IsAllDay:        false,
+			MeetingURL:      "https://zoom.us/j/1234567890",
+			MeetingProvider: ProviderZoom,
+			MeetingCode:     "1234567890",
+			Passcode:        "483921",
 		},
 	}
 
+	for _, event := range upcoming {
+		event.CalendarID = "primary"
+		event.Hidden = filter.hides(event)
+	}
+	markConflicts(upcoming)
+
 	// No events for today in demo
 	today := []*Event{}
 
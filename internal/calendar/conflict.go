@@ -0,0 +1,63 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// Conflict is two meetings that overlap
+type Conflict struct {
+	First  *Event
+	Second *Event
+	Start  time.Time // when the overlap starts
+	End    time.Time // when the overlap ends
+}
+
+// keepsBusy reports whether the event takes up time that could clash with
+// other meetings: a timed, shown event you haven't declined or marked as free
+func (e *Event) keepsBusy() bool {
+	return !e.IsAllDay && !e.Hidden && !e.IsDeclined() && e.Transparency != "transparent" &&
+		e.EndTime.After(e.StartTime)
+}
+
+// FindConflicts returns every pair of events in events that overlap, in
+// order of the overlap's start. All-day, hidden, declined and free events
+// don't conflict with anything.
+func FindConflicts(events []*Event) []Conflict {
+	var conflicts []Conflict
+	for i, first := range events {
+		if !first.keepsBusy() {
+			continue
+		}
+		for _, second := range events[i+1:] {
+			if !second.keepsBusy() || !first.StartTime.Before(second.EndTime) || !second.StartTime.Before(first.EndTime) {
+				continue
+			}
+			conflict := Conflict{First: first, Second: second, Start: first.StartTime, End: first.EndTime}
+			if second.StartTime.After(conflict.Start) {
+				conflict.Start = second.StartTime
+			}
+			if second.EndTime.Before(conflict.End) {
+				conflict.End = second.EndTime
+			}
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		return conflicts[i].Start.Before(conflicts[j].Start)
+	})
+	return conflicts
+}
+
+// markConflicts sets the Conflicts of every event in events to the other
+// events it overlaps
+func markConflicts(events []*Event) {
+	for _, event := range events {
+		event.Conflicts = nil
+	}
+	for _, c := range FindConflicts(events) {
+		c.First.Conflicts = append(c.First.Conflicts, c.Second)
+		c.Second.Conflicts = append(c.Second.Conflicts, c.First)
+	}
+}
@@ -0,0 +1,82 @@
+package calendar
+
+import (
+	"regexp"
+	"slices"
+)
+
+// Filter holds the rules for hiding noisy events; an event matching any rule
+// is hidden
+type Filter struct {
+	Titles      []*regexp.Regexp
+	Calendars   []string
+	EventTypes  []string
+	Declined    bool
+	AllDay      bool
+	NoAttendees bool
+}
+
+// filter is applied to every fetched event
+var filter Filter
+
+// SetFilter changes the rules used to mark events as hidden
+func SetFilter(f Filter) {
+	filter = f
+}
+
+// hides reports whether the filter hides the event
+func (f Filter) hides(e *Event) bool {
+	for _, re := range f.Titles {
+		if re.MatchString(e.Summary) {
+			return true
+		}
+	}
+	if slices.Contains(f.Calendars, e.CalendarID) {
+		return true
+	}
+	if e.EventType != "" && slices.Contains(f.EventTypes, e.EventType) {
+		return true
+	}
+	if f.Declined && e.IsDeclined() {
+		return true
+	}
+	if f.AllDay && e.IsAllDay {
+		return true
+	}
+	if f.NoAttendees && !e.HasOtherAttendees() {
+		return true
+	}
+	return false
+}
+
+// IsDeclined reports whether you declined the event
+func (e *Event) IsDeclined() bool {
+	for _, attendee := range e.Attendees {
+		if attendee.Self {
+			return attendee.ResponseStatus == "declined"
+		}
+	}
+	return false
+}
+
+// HasOtherAttendees reports whether anyone other than you (and rooms or
+// other resources) is invited
+func (e *Event) HasOtherAttendees() bool {
+	for _, attendee := range e.Attendees {
+		if !attendee.Self && !attendee.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// Visible returns the events the filter doesn't hide and how many it hid
+func Visible(events []*Event) ([]*Event, int) {
+	var visible []*Event
+	for _, event := range events {
+		if !event.Hidden {
+			visible = append(visible, event)
+		}
+	}
+	return visible, len(events) - len(visible)
+}
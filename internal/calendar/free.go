@@ -0,0 +1,209 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// Slot is a span of time, e.g. a busy period or an open slot
+type Slot struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns how long the slot lasts
+func (s Slot) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// WorkingHours is the part of each day free time is looked for in, as
+// minutes after midnight
+type WorkingHours struct {
+	Start int
+	End   int
+}
+
+// ParseWorkingHours parses working hours written as "09:00-17:30"
+func ParseWorkingHours(text string) (WorkingHours, error) {
+	from, to, found := strings.Cut(text, "-")
+	if !found {
+		return WorkingHours{}, fmt.Errorf("invalid working hours %q (want e.g. 09:00-17:30)", text)
+	}
+	start, err := parseClock(strings.TrimSpace(from))
+	if err != nil {
+		return WorkingHours{}, fmt.Errorf("invalid working hours %q: %v", text, err)
+	}
+	end, err := parseClock(strings.TrimSpace(to))
+	if err != nil {
+		return WorkingHours{}, fmt.Errorf("invalid working hours %q: %v", text, err)
+	}
+	if end <= start {
+		return WorkingHours{}, fmt.Errorf("invalid working hours %q: they end before they start", text)
+	}
+	return WorkingHours{Start: start, End: end}, nil
+}
+
+// parseClock parses "9:30" or "09:30" as minutes after midnight; "24:00"
+// is allowed as the end of the day
+func parseClock(text string) (int, error) {
+	if text == "24:00" {
+		return 24 * 60, nil
+	}
+	clock, err := time.Parse("15:04", text)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time like 09:30", text)
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+// On returns the working hours of day
+func (h WorkingHours) On(day time.Time) Slot {
+	return Slot{
+		Start: time.Date(day.Year(), day.Month(), day.Day(), 0, h.Start, 0, 0, time.Local),
+		End:   time.Date(day.Year(), day.Month(), day.Day(), 0, h.End, 0, 0, time.Local),
+	}
+}
+
+// FreeSlotOptions controls which open slots FindFreeSlots returns
+type FreeSlotOptions struct {
+	Hours    WorkingHours
+	Duration time.Duration // shortest slot worth returning
+	Buffer   time.Duration // time kept free before and after each meeting
+	Weekends bool          // also look on Saturdays and Sundays
+}
+
+// FetchBusy asks the FreeBusy API when each calendar (or person, by email
+// address) is busy in [from, to)
+func FetchBusy(srv *calendar.Service, ids []string, from time.Time, to time.Time) (map[string][]Slot, error) {
+	request := &calendar.FreeBusyRequest{
+		TimeMin: from.Format(time.RFC3339),
+		TimeMax: to.Format(time.RFC3339),
+	}
+	for _, id := range ids {
+		request.Items = append(request.Items, &calendar.FreeBusyRequestItem{Id: id})
+	}
+
+	result, err := srv.Freebusy.Query(request).Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to query free/busy times: %v", err)
+	}
+
+	busy := make(map[string][]Slot, len(ids))
+	for _, id := range ids {
+		cal, ok := result.Calendars[id]
+		if !ok {
+			return nil, fmt.Errorf("calendar %s: no free/busy information", id)
+		}
+		if len(cal.Errors) > 0 {
+			return nil, fmt.Errorf("calendar %s: %s", id, cal.Errors[0].Reason)
+		}
+		var slots []Slot
+		for _, period := range cal.Busy {
+			start, err := time.Parse(time.RFC3339, period.Start)
+			if err != nil {
+				continue
+			}
+			end, err := time.Parse(time.RFC3339, period.End)
+			if err != nil {
+				continue
+			}
+			slots = append(slots, Slot{Start: start.In(time.Local), End: end.In(time.Local)})
+		}
+		busy[id] = slots
+	}
+	return busy, nil
+}
+
+// FetchOwnBusy returns when any of the configured calendars is busy in
+// [from, to)
+func FetchOwnBusy(srv *calendar.Service, from time.Time, to time.Time) ([]Slot, error) {
+	busy, err := FetchBusy(srv, calendarIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var slots []Slot
+	for _, id := range calendarIDs {
+		slots = append(slots, busy[id]...)
+	}
+	return MergeSlots(slots), nil
+}
+
+// BusyTimes works out when events keep you busy, the way the FreeBusy API
+// does, for events that didn't come from it (e.g. the demo events). All-day,
+// declined and "free" (transparent) events don't count.
+func BusyTimes(events []*Event) []Slot {
+	var slots []Slot
+	for _, event := range events {
+		if event.IsAllDay || event.IsDeclined() || event.Transparency == "transparent" {
+			continue
+		}
+		slots = append(slots, Slot{Start: event.StartTime, End: event.EndTime})
+	}
+	return MergeSlots(slots)
+}
+
+// MergeSlots sorts slots and joins those that overlap or touch
+func MergeSlots(slots []Slot) []Slot {
+	sorted := make([]Slot, len(slots))
+	copy(sorted, slots)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	var merged []Slot
+	for _, slot := range sorted {
+		if n := len(merged); n > 0 && !slot.Start.After(merged[n-1].End) {
+			if slot.End.After(merged[n-1].End) {
+				merged[n-1].End = slot.End
+			}
+			continue
+		}
+		merged = append(merged, slot)
+	}
+	return merged
+}
+
+// FindFreeSlots returns the open slots in [from, to) within working hours
+// that are at least opts.Duration long, keeping opts.Buffer free around
+// every busy period
+func FindFreeSlots(busy []Slot, from time.Time, to time.Time, opts FreeSlotOptions) []Slot {
+	busy = MergeSlots(busy)
+
+	var free []Slot
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !opts.Weekends && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+
+		hours := opts.Hours.On(day)
+		if hours.Start.Before(from) {
+			hours.Start = from
+		}
+		if hours.End.After(to) {
+			hours.End = to
+		}
+
+		cursor := hours.Start
+		for _, b := range busy {
+			start, end := b.Start.Add(-opts.Buffer), b.End.Add(opts.Buffer)
+			if !end.After(cursor) {
+				continue
+			}
+			if !start.Before(hours.End) {
+				break
+			}
+			if start.Sub(cursor) >= opts.Duration {
+				free = append(free, Slot{Start: cursor, End: start})
+			}
+			cursor = end
+		}
+		if hours.End.Sub(cursor) >= opts.Duration {
+			free = append(free, Slot{Start: cursor, End: hours.End})
+		}
+	}
+	return free
+}
@@ -0,0 +1,135 @@
+package calendar
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseWorkingHours(t *testing.T) {
+	tests := []struct {
+		text string
+		want WorkingHours
+		ok   bool
+	}{
+		{"09:00-17:30", WorkingHours{Start: 9 * 60, End: 17*60 + 30}, true},
+		{"9:30-17:00", WorkingHours{Start: 9*60 + 30, End: 17 * 60}, true},
+		{"22:00-24:00", WorkingHours{Start: 22 * 60, End: 24 * 60}, true},
+		{"+9:00-17:00", WorkingHours{}, false},
+		{"09:00-+17:00", WorkingHours{}, false},
+		{"09:0-17:00", WorkingHours{}, false},
+		{"09:60-17:00", WorkingHours{}, false},
+		{"17:00-09:00", WorkingHours{}, false},
+		{"0900-1700", WorkingHours{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseWorkingHours(tt.text)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseWorkingHours(%q) = %v, %v", tt.text, got, err)
+		}
+	}
+}
+
+// at returns the given time on Monday, October 19, 2026, or days after it
+func at(days int, hour int, minute int) time.Time {
+	return time.Date(2026, time.October, 19+days, hour, minute, 0, 0, time.Local)
+}
+
+func TestMergeSlots(t *testing.T) {
+	tests := []struct {
+		name  string
+		slots []Slot
+		want  []Slot
+	}{
+		{"empty", nil, nil},
+		{
+			"overlapping and unsorted",
+			[]Slot{{at(0, 11, 0), at(0, 12, 0)}, {at(0, 9, 0), at(0, 10, 0)}, {at(0, 9, 30), at(0, 11, 15)}},
+			[]Slot{{at(0, 9, 0), at(0, 12, 0)}},
+		},
+		{
+			"touching",
+			[]Slot{{at(0, 9, 0), at(0, 10, 0)}, {at(0, 10, 0), at(0, 11, 0)}},
+			[]Slot{{at(0, 9, 0), at(0, 11, 0)}},
+		},
+		{
+			"contained",
+			[]Slot{{at(0, 9, 0), at(0, 12, 0)}, {at(0, 10, 0), at(0, 11, 0)}},
+			[]Slot{{at(0, 9, 0), at(0, 12, 0)}},
+		},
+		{
+			"apart",
+			[]Slot{{at(0, 13, 0), at(0, 14, 0)}, {at(0, 9, 0), at(0, 10, 0)}},
+			[]Slot{{at(0, 9, 0), at(0, 10, 0)}, {at(0, 13, 0), at(0, 14, 0)}},
+		},
+	}
+	for _, tt := range tests {
+		if got := MergeSlots(tt.slots); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: MergeSlots() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFindFreeSlots(t *testing.T) {
+	hours := WorkingHours{Start: 9 * 60, End: 17 * 60}
+
+	tests := []struct {
+		name     string
+		busy     []Slot
+		from, to time.Time
+		opts     FreeSlotOptions
+		want     []Slot
+	}{
+		{
+			"whole day free",
+			nil, at(0, 0, 0), at(1, 0, 0),
+			FreeSlotOptions{Hours: hours, Duration: 30 * time.Minute},
+			[]Slot{{at(0, 9, 0), at(0, 17, 0)}},
+		},
+		{
+			"gaps between meetings",
+			[]Slot{{at(0, 10, 0), at(0, 11, 0)}, {at(0, 10, 30), at(0, 12, 0)}, {at(0, 16, 0), at(0, 18, 0)}},
+			at(0, 0, 0), at(1, 0, 0),
+			FreeSlotOptions{Hours: hours, Duration: 30 * time.Minute},
+			[]Slot{{at(0, 9, 0), at(0, 10, 0)}, {at(0, 12, 0), at(0, 16, 0)}},
+		},
+		{
+			"too short with buffer",
+			[]Slot{{at(0, 9, 30), at(0, 10, 0)}, {at(0, 10, 30), at(0, 17, 0)}},
+			at(0, 0, 0), at(1, 0, 0),
+			FreeSlotOptions{Hours: hours, Duration: 30 * time.Minute, Buffer: 10 * time.Minute},
+			nil,
+		},
+		{
+			"starts mid-day",
+			[]Slot{{at(0, 15, 0), at(0, 16, 0)}},
+			at(0, 13, 20), at(1, 0, 0),
+			FreeSlotOptions{Hours: hours, Duration: 30 * time.Minute},
+			[]Slot{{at(0, 13, 20), at(0, 15, 0)}, {at(0, 16, 0), at(0, 17, 0)}},
+		},
+		{
+			"weekend skipped",
+			nil, at(5, 0, 0), at(7, 0, 0),
+			FreeSlotOptions{Hours: hours, Duration: 30 * time.Minute},
+			nil,
+		},
+		{
+			"weekend included",
+			nil, at(5, 0, 0), at(6, 0, 0),
+			FreeSlotOptions{Hours: hours, Duration: 30 * time.Minute, Weekends: true},
+			[]Slot{{at(5, 9, 0), at(5, 17, 0)}},
+		},
+		{
+			"meeting across midnight",
+			[]Slot{{at(0, 16, 0), at(1, 10, 0)}},
+			at(0, 0, 0), at(2, 0, 0),
+			FreeSlotOptions{Hours: hours, Duration: 30 * time.Minute},
+			[]Slot{{at(0, 9, 0), at(0, 16, 0)}, {at(1, 10, 0), at(1, 17, 0)}},
+		},
+	}
+	for _, tt := range tests {
+		if got := FindFreeSlots(tt.busy, tt.from, tt.to, tt.opts); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: FindFreeSlots() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package calendar
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// Meeting providers recognized in event links
+const (
+	ProviderMeet    = "Meet"
+	ProviderZoom    = "Zoom"
+	ProviderTeams   = "Teams"
+	ProviderWebex   = "Webex"
+	ProviderJitsi   = "Jitsi"
+	ProviderWhereby = "Whereby"
+	ProviderGoTo    = "GoTo"
+	ProviderChime   = "Chime"
+)
+
+// meetingPattern matches a provider's meeting URLs
+type meetingPattern struct {
+	provider string
+	re       *regexp.Regexp
+}
+
+// urlTail matches the rest of a URL up to whitespace, quotes or markup
+const urlTail = `[^\s"'<>()\[\]]*`
+
+// meetingPatterns lists the providers in the order they are tried
+var meetingPatterns = []meetingPattern{
+	{ProviderMeet, regexp.MustCompile(`https://meet\.google\.com/[a-z]{3}-[a-z]{4}-[a-z]{3}` + urlTail)},
+	{ProviderZoom, regexp.MustCompile(`https://[\w.-]*zoom(gov)?\.(us|com)/(j|my|w|s)/` + urlTail)},
+	{ProviderTeams, regexp.MustCompile(`https://teams\.(microsoft|live)\.com/(l/meetup-join|meet)/` + urlTail)},
+	{ProviderWebex, regexp.MustCompile(`https://[\w.-]+\.webex\.com/(meet/|join/|wbxmjs/joinservice|([\w.-]+/)?j\.php)` + urlTail)},
+	{ProviderJitsi, regexp.MustCompile(`https://meet\.jit\.si/` + urlTail)},
+	{ProviderWhereby, regexp.MustCompile(`https://whereby\.com/` + urlTail)},
+	{ProviderGoTo, regexp.MustCompile(`https://(meet\.goto\.com|global\.gotomeeting\.com/join)/` + urlTail)},
+	{ProviderChime, regexp.MustCompile(`https://chime\.aws/[0-9]+` + urlTail)},
+}
+
+// findMeeting returns the event's video meeting URL and its provider. It
+// looks at the conference data (preferring video over phone entry points),
+// then the Hangouts link, then provider links in the location and
+// description, and finally falls back to a location that is a URL, whose
+// provider is empty.
+func findMeeting(e *calendar.Event) (string, string) {
+	if e.ConferenceData != nil {
+		for _, entry := range e.ConferenceData.EntryPoints {
+			if entry.EntryPointType == "video" && entry.Uri != "" {
+				return entry.Uri, conferenceProvider(entry.Uri, e.ConferenceData)
+			}
+		}
+	}
+
+	if e.HangoutLink != "" {
+		return e.HangoutLink, ProviderMeet
+	}
+
+	for _, text := range []string{e.Location, e.Description} {
+		if link, provider := matchMeetingURL(text); link != "" {
+			return link, provider
+		}
+	}
+
+	if strings.HasPrefix(e.Location, "http") {
+		return e.Location, ""
+	}
+	return "", ""
+}
+
+// matchMeetingURL returns the first known provider's meeting URL in text
+func matchMeetingURL(text string) (string, string) {
+	// Descriptions are often HTML with escaped query strings
+	text = strings.ReplaceAll(text, "&amp;", "&")
+	for _, p := range meetingPatterns {
+		if link := p.re.FindString(text); link != "" {
+			return strings.TrimRight(link, ".,;"), p.provider
+		}
+	}
+	return "", ""
+}
+
+// conferenceProvider names the provider of a conference entry point, from
+// its URL or else the conference solution's name
+func conferenceProvider(uri string, data *calendar.ConferenceData) string {
+	if _, provider := matchMeetingURL(uri); provider != "" {
+		return provider
+	}
+	if data.ConferenceSolution != nil {
+		return strings.TrimPrefix(data.ConferenceSolution.Name, "Google ")
+	}
+	return ""
+}
+
+// zoomMeeting matches a Zoom join link's host and meeting ID
+var zoomMeeting = regexp.MustCompile(`^https://([\w.-]*zoom(?:gov)?\.(?:us|com))/(?:j|w|s)/(\d+)`)
+
+// MeetingID returns the provider's ID for the meeting, e.g. the Zoom meeting
+// number or the Meet code, or "" if it is not known
+func (e *Event) MeetingID() string {
+	if e.MeetingCode != "" {
+		return e.MeetingCode
+	}
+
+	u, err := url.Parse(e.MeetingURL)
+	if err != nil {
+		return ""
+	}
+
+	switch e.MeetingProvider {
+	case ProviderZoom:
+		if m := zoomMeeting.FindStringSubmatch(e.MeetingURL); m != nil {
+			return m[2]
+		}
+	case ProviderMeet, ProviderJitsi, ProviderWhereby, ProviderGoTo, ProviderChime:
+		return path.Base(u.Path)
+	}
+	return ""
+}
+
+// NativeMeetingURL returns a URI that opens the meeting in the provider's
+// desktop app (e.g. zoommtg:// or msteams:), or MeetingURL when the provider
+// has none
+func (e *Event) NativeMeetingURL() string {
+	switch e.MeetingProvider {
+	case ProviderZoom:
+		m := zoomMeeting.FindStringSubmatch(e.MeetingURL)
+		if m == nil {
+			break
+		}
+		native := "zoommtg://" + m[1] + "/join?action=join&confno=" + m[2]
+		if u, err := url.Parse(e.MeetingURL); err == nil && u.Query().Get("pwd") != "" {
+			native += "&pwd=" + url.QueryEscape(u.Query().Get("pwd"))
+		}
+		return native
+	case ProviderTeams:
+		if u, err := url.Parse(e.MeetingURL); err == nil {
+			u.Scheme = "msteams"
+			u.Host = ""
+			return strings.Replace(u.String(), "msteams:///", "msteams:/", 1)
+		}
+	}
+	return e.MeetingURL
+}
+
+// DialIn is a phone number for joining a meeting
+type DialIn struct {
+	Number string // formatted for display, e.g. "+1 929-205-6099"
+	Region string // e.g. "US"
+	PIN    string // access code or PIN to enter after dialing
+}
+
+// Meeting IDs and passcodes as written in Zoom and Teams invitations
+var (
+	meetingIDText = regexp.MustCompile(`(?i)meeting id:?\s*(\d[\d ]{7,}\d)`)
+	passcodeText  = regexp.MustCompile(`(?i)\b(?:passcode|password)\s*[:=]\s*([A-Za-z0-9]{3,})`)
+)
+
+// meetingDetails returns the meeting code, passcode and dial-in numbers from
+// the event's conference data, falling back to a meeting ID and passcode
+// written in the description
+func meetingDetails(e *calendar.Event) (string, string, []DialIn) {
+	var code, passcode string
+	var dialIns []DialIn
+
+	if e.ConferenceData != nil {
+		for _, entry := range e.ConferenceData.EntryPoints {
+			switch entry.EntryPointType {
+			case "video":
+				code = entry.MeetingCode
+				passcode = firstNonEmpty(entry.Passcode, entry.Password, entry.Pin, entry.AccessCode)
+			case "phone":
+				number := entry.Label
+				if number == "" {
+					number = strings.TrimPrefix(entry.Uri, "tel:")
+				}
+				dialIns = append(dialIns, DialIn{
+					Number: number,
+					Region: entry.RegionCode,
+					PIN:    firstNonEmpty(entry.Pin, entry.AccessCode, entry.Passcode, entry.Password),
+				})
+			}
+		}
+		if code == "" {
+			code = e.ConferenceData.ConferenceId
+		}
+	}
+
+	if m := meetingIDText.FindStringSubmatch(e.Description); code == "" && m != nil {
+		code = strings.ReplaceAll(m[1], " ", "")
+	}
+	if m := passcodeText.FindStringSubmatch(e.Description); passcode == "" && m != nil {
+		passcode = m[1]
+	}
+	return code, passcode, dialIns
+}
+
+// firstNonEmpty returns the first of values that isn't empty
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package calendar
+
+import (
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestMeetingDetailsPasscode(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"Join Zoom Meeting\nMeeting ID: 812 3456 7890\nPasscode: 493021", "493021"},
+		{"Meeting ID: 123 456 789 012<br>Passcode: aB3xYz<br>", "aB3xYz"},
+		{"password = hunter2", "hunter2"},
+		{"This meeting is password protected; call me", ""},
+		{"Passcode sent separately", ""},
+		{"Password: ab", ""},
+	}
+	for _, tt := range tests {
+		_, got, _ := meetingDetails(&calendar.Event{Description: tt.description})
+		if got != tt.want {
+			t.Errorf("meetingDetails(%q) passcode = %q, want %q", tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestFindMeetingWebex(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"Join https://acme.webex.com/meet/jdoe", "https://acme.webex.com/meet/jdoe"},
+		{"Join https://acme.webex.com/join/jdoe", "https://acme.webex.com/join/jdoe"},
+		{"Join https://acme.webex.com/acme/j.php?MTID=m123", "https://acme.webex.com/acme/j.php?MTID=m123"},
+		{"Join https://acme.webex.com/wbxmjs/joinservice/sites/acme/meeting/abc", "https://acme.webex.com/wbxmjs/joinservice/sites/acme/meeting/abc"},
+		{"Help: https://help.webex.com/en-us/article/123", ""},
+		{"Get the app at https://www.webex.com/downloads.html", ""},
+	}
+	for _, tt := range tests {
+		got, _ := findMeeting(&calendar.Event{Description: tt.description})
+		if got != tt.want {
+			t.Errorf("findMeeting(%q) = %q, want %q", tt.description, got, tt.want)
+		}
+	}
+}
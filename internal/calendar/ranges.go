@@ -0,0 +1,87 @@
+package calendar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRange returns the time span a range name covers relative to now:
+// "today", "tomorrow", "this week", "next week", "last week", "this month",
+// "next month", "last month", a number of days or weeks starting today
+// ("7d", "next 2w") or the whole days before today ("last 30d", which ends at
+// today's midnight). "this", "next" and "last" may be followed by a hyphen
+// instead of a space, e.g. "last-30d"; no other hyphens are allowed. Weeks
+// start on Monday.
+func ParseRange(text string, now time.Time) (time.Time, time.Time, error) {
+	lower := strings.ToLower(strings.TrimSpace(text))
+	for _, word := range []string{"this", "next", "last"} {
+		if rest, ok := strings.CutPrefix(lower, word+"-"); ok {
+			lower = word + " " + rest
+		}
+	}
+	if strings.Contains(lower, "-") {
+		return time.Time{}, time.Time{}, unknownRange(text)
+	}
+	words := strings.Fields(lower)
+	today := startOfDay(now)
+	weekStart := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	switch strings.Join(words, " ") {
+	case "today":
+		return today, today.AddDate(0, 0, 1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), nil
+	case "this week":
+		return weekStart, weekStart.AddDate(0, 0, 7), nil
+	case "next week":
+		return weekStart.AddDate(0, 0, 7), weekStart.AddDate(0, 0, 14), nil
+	case "last week":
+		return weekStart.AddDate(0, 0, -7), weekStart, nil
+	case "this month":
+		return monthStart, monthStart.AddDate(0, 1, 0), nil
+	case "next month":
+		return monthStart.AddDate(0, 1, 0), monthStart.AddDate(0, 2, 0), nil
+	case "last month":
+		return monthStart.AddDate(0, -1, 0), monthStart, nil
+	}
+
+	if len(words) == 1 || (len(words) == 2 && (words[0] == "next" || words[0] == "last")) {
+		if days, ok := parseDays(words[len(words)-1]); ok {
+			if words[0] == "last" {
+				return today.AddDate(0, 0, -days), today, nil
+			}
+			return today, today.AddDate(0, 0, days), nil
+		}
+	}
+	return time.Time{}, time.Time{}, unknownRange(text)
+}
+
+// unknownRange returns the error for a range ParseRange doesn't understand
+func unknownRange(text string) error {
+	return fmt.Errorf("unknown range %q (want today, tomorrow, this/next/last week or month, or a length like 7d, 2w or last-30d)", text)
+}
+
+// parseDays parses a positive number of days ("30d") or weeks ("2w") as days
+func parseDays(text string) (int, bool) {
+	if len(text) < 2 {
+		return 0, false
+	}
+	digits := text[:len(text)-1]
+	if strings.Trim(digits, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	switch text[len(text)-1] {
+	case 'd':
+		return n, true
+	case 'w':
+		return n * 7, true
+	}
+	return 0, false
+}
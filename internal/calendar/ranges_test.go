@@ -0,0 +1,47 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRange(t *testing.T) {
+	// Wednesday afternoon
+	now := time.Date(2026, time.October, 21, 15, 30, 0, 0, time.Local)
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2026, month, d, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		text     string
+		from, to time.Time
+	}{
+		{"today", day(time.October, 21), day(time.October, 22)},
+		{"Tomorrow", day(time.October, 22), day(time.October, 23)},
+		{"this week", day(time.October, 19), day(time.October, 26)},
+		{"next-week", day(time.October, 26), day(time.November, 2)},
+		{"last week", day(time.October, 12), day(time.October, 19)},
+		{"this month", day(time.October, 1), day(time.November, 1)},
+		{"next month", day(time.November, 1), day(time.December, 1)},
+		{"last-month", day(time.September, 1), day(time.October, 1)},
+		{"3d", day(time.October, 21), day(time.October, 24)},
+		{"next 2w", day(time.October, 21), day(time.November, 4)},
+		{"last-30d", day(time.September, 21), day(time.October, 21)},
+		{"last 1d", day(time.October, 20), day(time.October, 21)},
+	}
+	for _, tt := range tests {
+		from, to, err := ParseRange(tt.text, now)
+		if err != nil || !from.Equal(tt.from) || !to.Equal(tt.to) {
+			t.Errorf("ParseRange(%q) = %v, %v, %v, want %v, %v", tt.text, from, to, err, tt.from, tt.to)
+		}
+	}
+}
+
+func TestParseRangeInvalid(t *testing.T) {
+	now := time.Date(2026, time.October, 21, 15, 30, 0, 0, time.Local)
+	for _, text := range []string{"", "-5d", "5-d", "last--30d", "+5d", "0d", "3x", "d", "last", "last week-", "yesterday", "next 2w 3d"} {
+		if from, to, err := ParseRange(text, now); err == nil {
+			t.Errorf("ParseRange(%q) = %v, %v, want an error", text, from, to)
+		}
+	}
+}
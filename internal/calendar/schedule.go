@@ -0,0 +1,190 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// Participant is someone a meeting is being scheduled with
+type Participant struct {
+	Email string
+	Zone  *time.Location // nil when their calendar's time zone isn't visible to us
+	Busy  []Slot
+}
+
+// Candidate is a possible meeting time with who can't make it
+type Candidate struct {
+	Slot
+
+	// Busy lists the participants with something else on at the time
+	Busy []string
+
+	// Outside lists the participants for whom the time falls outside working
+	// hours in their own time zone, and OutsideHours sums how far outside
+	Outside      []string
+	OutsideHours time.Duration
+}
+
+// FetchParticipants returns when you and each of emails are busy in
+// [from, to), along with their time zones where their calendars share them.
+// You come first, as "me".
+func FetchParticipants(srv *calendar.Service, emails []string, from time.Time, to time.Time) ([]Participant, error) {
+	ids := append(append([]string{}, calendarIDs...), emails...)
+	busy, err := FetchBusy(srv, ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var own []Slot
+	for _, id := range calendarIDs {
+		own = append(own, busy[id]...)
+	}
+	participants := []Participant{{Email: "me", Zone: time.Local, Busy: MergeSlots(own)}}
+
+	for _, email := range emails {
+		participant := Participant{Email: email, Busy: MergeSlots(busy[email])}
+		// Only colleagues sharing their calendar details reveal their zone
+		if cal, err := srv.Calendars.Get(email).Do(); err == nil && cal.TimeZone != "" {
+			participant.Zone, _ = time.LoadLocation(cal.TimeZone)
+		}
+		participants = append(participants, participant)
+	}
+	return participants, nil
+}
+
+// IsBusy reports whether the participant has something on at any time in
+// [from, to)
+func (p Participant) IsBusy(from time.Time, to time.Time) bool {
+	for _, slot := range p.Busy {
+		if slot.Start.Before(to) && slot.End.After(from) {
+			return true
+		}
+	}
+	return false
+}
+
+// RankCandidates returns every opts.Duration long meeting time in [from, to)
+// starting on a multiple of step within your working hours, best first:
+// fewest participants busy, then least time outside the participants'
+// working hours (which are assumed to match yours, in their own zones), then
+// earliest.
+func RankCandidates(participants []Participant, from time.Time, to time.Time, opts FreeSlotOptions, step time.Duration) []Candidate {
+	var candidates []Candidate
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !opts.Weekends && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+
+		hours := opts.Hours.On(day)
+		for start := hours.Start; !start.Add(opts.Duration).After(hours.End); start = start.Add(step) {
+			end := start.Add(opts.Duration)
+			if start.Before(from) || end.After(to) {
+				continue
+			}
+
+			candidate := Candidate{Slot: Slot{Start: start, End: end}}
+			for _, p := range participants {
+				if p.IsBusy(start, end) {
+					candidate.Busy = append(candidate.Busy, p.Email)
+				}
+				if p.Zone == nil {
+					continue
+				}
+				if outside := opts.Hours.outside(candidate.Slot, p.Zone); outside > 0 {
+					candidate.Outside = append(candidate.Outside, p.Email)
+					candidate.OutsideHours += outside
+				}
+			}
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if len(a.Busy) != len(b.Busy) {
+			return len(a.Busy) < len(b.Busy)
+		}
+		return a.OutsideHours < b.OutsideHours
+	})
+	return candidates
+}
+
+// outside returns how much of slot falls outside the working hours of the
+// days it covers in zone
+func (h WorkingHours) outside(slot Slot, zone *time.Location) time.Duration {
+	inside := time.Duration(0)
+	start := slot.Start.In(zone)
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, zone); day.Before(slot.End); day = day.AddDate(0, 0, 1) {
+		work := Slot{
+			Start: time.Date(day.Year(), day.Month(), day.Day(), 0, h.Start, 0, 0, zone),
+			End:   time.Date(day.Year(), day.Month(), day.Day(), 0, h.End, 0, 0, zone),
+		}
+		from, to := work.Start, work.End
+		if slot.Start.After(from) {
+			from = slot.Start
+		}
+		if slot.End.Before(to) {
+			to = slot.End
+		}
+		if to.After(from) {
+			inside += to.Sub(from)
+		}
+	}
+	return slot.Duration() - inside
+}
+
+// CreateMeeting adds a meeting to your primary calendar and invites the
+// attendees by email, with a Google Meet link when addMeet is set
+func CreateMeeting(srv *calendar.Service, title string, slot Slot, attendees []string, addMeet bool) (*Event, error) {
+	event := &calendar.Event{
+		Summary: title,
+		Start:   &calendar.EventDateTime{DateTime: slot.Start.Format(time.RFC3339)},
+		End:     &calendar.EventDateTime{DateTime: slot.End.Format(time.RFC3339)},
+	}
+	for _, email := range attendees {
+		event.Attendees = append(event.Attendees, &calendar.EventAttendee{Email: email})
+	}
+
+	call := srv.Events.Insert("primary", event).SendUpdates("all")
+	if addMeet {
+		event.ConferenceData = &calendar.ConferenceData{
+			CreateRequest: &calendar.CreateConferenceRequest{
+				RequestId:             fmt.Sprintf("mycal-%d", time.Now().UnixNano()),
+				ConferenceSolutionKey: &calendar.ConferenceSolutionKey{Type: "hangoutsMeet"},
+			},
+		}
+		call = call.ConferenceDataVersion(1)
+	}
+
+	created, err := call.Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create event: %w", err)
+	}
+	return wrapEvent(created), nil
+}
+
+// GetDemoParticipants returns made-up availability for you and emails over
+// the two weeks from from's day, for trying schedule without a calendar
+func GetDemoParticipants(emails []string, from time.Time) []Participant {
+	today, upcoming, _ := GetDemoEvents()
+	participants := []Participant{{Email: "me", Zone: time.Local, Busy: BusyTimes(append(today, upcoming...))}}
+
+	zones := []string{"Europe/London", "America/New_York", "Asia/Kolkata"}
+	for i, email := range emails {
+		participant := Participant{Email: email}
+		participant.Zone, _ = time.LoadLocation(zones[i%len(zones)])
+
+		for day := startOfDay(from); day.Before(startOfDay(from).AddDate(0, 0, 14)); day = day.AddDate(0, 0, 1) {
+			hour := 10 + (i*2+day.Day())%6
+			participant.Busy = append(participant.Busy, Slot{
+				Start: time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, time.Local),
+				End:   time.Date(day.Year(), day.Month(), day.Day(), hour+1, 0, 0, 0, time.Local),
+			})
+		}
+		participants = append(participants, participant)
+	}
+	return participants
+}
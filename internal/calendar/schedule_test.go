@@ -0,0 +1,68 @@
+package calendar
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRankCandidates(t *testing.T) {
+	// Bob works three hours ahead, so his day ends at 14:00 ours
+	_, offset := at(0, 9, 0).Zone()
+	ahead := time.FixedZone("Ahead", offset+3*60*60)
+
+	participants := []Participant{
+		{Email: "me", Busy: []Slot{{at(0, 9, 0), at(0, 10, 0)}}},
+		{Email: "alice@example.com", Busy: []Slot{{at(0, 9, 0), at(0, 11, 0)}}},
+		{Email: "bob@example.com", Zone: ahead},
+	}
+	opts := FreeSlotOptions{Hours: WorkingHours{Start: 9 * 60, End: 17 * 60}, Duration: time.Hour}
+	candidates := RankCandidates(participants, at(0, 0, 0), at(1, 0, 0), opts, 30*time.Minute)
+
+	var starts []time.Time
+	for _, c := range candidates {
+		starts = append(starts, c.Start)
+	}
+	want := []time.Time{
+		// Nobody busy, within everyone's hours
+		at(0, 11, 0), at(0, 11, 30), at(0, 12, 0), at(0, 12, 30), at(0, 13, 0),
+		// Nobody busy, partly after Bob's hours
+		at(0, 13, 30),
+		// Nobody busy, after Bob's hours
+		at(0, 14, 0), at(0, 14, 30), at(0, 15, 0), at(0, 15, 30), at(0, 16, 0),
+		// Alice busy
+		at(0, 10, 0), at(0, 10, 30),
+		// Both of us busy
+		at(0, 9, 0), at(0, 9, 30),
+	}
+	if !slices.Equal(starts, want) {
+		t.Fatalf("candidate starts = %v, want %v", starts, want)
+	}
+
+	if c := candidates[5]; c.OutsideHours != 30*time.Minute || !slices.Equal(c.Outside, []string{"bob@example.com"}) {
+		t.Errorf("13:30 candidate = %+v, want 30m outside Bob's hours", c)
+	}
+	if c := candidates[13]; !slices.Equal(c.Busy, []string{"me", "alice@example.com"}) {
+		t.Errorf("9:00 candidate busy = %v, want me and Alice", c.Busy)
+	}
+}
+
+func TestRankCandidatesBounds(t *testing.T) {
+	opts := FreeSlotOptions{Hours: WorkingHours{Start: 9 * 60, End: 17 * 60}, Duration: 90 * time.Minute}
+
+	// Starting mid-afternoon on Friday, through the weekend
+	candidates := RankCandidates(nil, at(4, 14, 45), at(7, 0, 0), opts, 30*time.Minute)
+	var starts []time.Time
+	for _, c := range candidates {
+		starts = append(starts, c.Start)
+	}
+	want := []time.Time{at(4, 15, 0), at(4, 15, 30)}
+	if !slices.Equal(starts, want) {
+		t.Errorf("candidate starts = %v, want %v", starts, want)
+	}
+
+	opts.Weekends = true
+	if n := len(RankCandidates(nil, at(5, 0, 0), at(6, 0, 0), opts, time.Hour)); n != 7 {
+		t.Errorf("got %d Saturday candidates with weekends, want 7", n)
+	}
+}
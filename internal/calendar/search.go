@@ -0,0 +1,109 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// SearchEvents retrieves the events in [from, to) that the Calendar API
+// matches against query (title, description, location, attendees, ...)
+func SearchEvents(srv *calendar.Service, query string, from time.Time, to time.Time) ([]*Event, error) {
+	return listEvents(srv, func(call *calendar.EventsListCall) *calendar.EventsListCall {
+		return call.Q(query).TimeMin(from.Format(time.RFC3339)).TimeMax(to.Format(time.RFC3339))
+	}, nil)
+}
+
+// FilterEvents returns the events that fuzzy-match query
+func FilterEvents(events []*Event, query string) []*Event {
+	var matched []*Event
+	for _, event := range events {
+		if event.Matches(query) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// Matches reports whether every word of query fuzzy-matches the event's
+// title, location, description or attendee names
+func (e *Event) Matches(query string) bool {
+	fields := []string{e.Summary, e.Location, e.Description}
+	for _, attendee := range e.Attendees {
+		fields = append(fields, attendee.DisplayName, attendee.Email)
+	}
+
+	for _, word := range strings.Fields(query) {
+		found := false
+		for _, field := range fields {
+			if FuzzyMatch(field, word) != nil {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// FuzzyMatch returns the rune indexes of text that match query's characters
+// in order, ignoring case, or nil if they don't. Matches are only accepted
+// when the characters lie close together (within three times the query's
+// length) so long descriptions don't match everything.
+func FuzzyMatch(text string, query string) []int {
+	// Lower each rune on its own: lowering the whole string can change its
+	// length and shift the indexes away from text's runes
+	t := []rune(text)
+	q := []rune(query)
+	for i := range t {
+		t[i] = unicode.ToLower(t[i])
+	}
+	for i := range q {
+		q[i] = unicode.ToLower(q[i])
+	}
+	if len(q) == 0 {
+		return nil
+	}
+
+	// Try every start position and keep the shortest window
+	var best []int
+	for start := range t {
+		if t[start] != q[0] {
+			continue
+		}
+		indexes := []int{start}
+		for i := start + 1; i < len(t) && len(indexes) < len(q); i++ {
+			if t[i] == q[len(indexes)] {
+				indexes = append(indexes, i)
+			}
+		}
+		if len(indexes) < len(q) {
+			break
+		}
+		span := indexes[len(indexes)-1] - start
+		if best == nil || span < best[len(best)-1]-best[0] {
+			best = indexes
+		}
+	}
+
+	if best == nil || best[len(best)-1]-best[0]+1 > 3*len(q) {
+		return nil
+	}
+	return best
+}
+
+// MatchIndexes returns the rune indexes of text matched by any word of query,
+// for highlighting
+func MatchIndexes(text string, query string) map[int]bool {
+	matched := make(map[int]bool)
+	for _, word := range strings.Fields(query) {
+		for _, i := range FuzzyMatch(text, word) {
+			matched[i] = true
+		}
+	}
+	return matched
+}
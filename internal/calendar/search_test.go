@@ -0,0 +1,44 @@
+package calendar
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		text  string
+		query string
+		want  []int
+	}{
+		{"Team Standup", "stand", []int{5, 6, 7, 8, 9}},
+		{"Team Standup", "STDP", []int{5, 6, 9, 11}},
+		{"Design Review", "dr", nil}, // too far apart
+		{"Design Review", "rev", []int{7, 8, 9}},
+		{"Planning", "gnp", nil}, // out of order
+		{"Planning", "", nil},
+		// The shortest window wins over the first one
+		{"a-----b ab", "ab", []int{8, 9}},
+		// Indexes count the original runes, whatever their case
+		{"İstanbul Sync", "sync", []int{9, 10, 11, 12}},
+		{"Übergabe", "üb", []int{0, 1}},
+		{"Kickoff", "kick", []int{0, 1, 2, 3}}, // Kelvin sign
+	}
+	for _, tt := range tests {
+		if got := FuzzyMatch(tt.text, tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("FuzzyMatch(%q, %q) = %v, want %v", tt.text, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestMatchIndexes(t *testing.T) {
+	got := MatchIndexes("Weekly Sync", "wk sync")
+	for _, i := range []int{0, 3, 7, 8, 9, 10} {
+		if !got[i] {
+			t.Errorf("MatchIndexes() = %v, want index %d", got, i)
+		}
+	}
+	if len(got) != 6 {
+		t.Errorf("MatchIndexes() = %v, want 6 indexes", got)
+	}
+}
@@ -0,0 +1,205 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// backToBackGap is the longest break between two meetings that still counts
+// as back-to-back
+const backToBackGap = 5 * time.Minute
+
+// maxStatsEntries is how many focus blocks, recurring meetings, organizers
+// and calendars Stats keeps
+const maxStatsEntries = 5
+
+// Stats summarizes how much of a date range went to meetings
+type Stats struct {
+	From     time.Time
+	To       time.Time
+	Meetings int
+	Total    time.Duration // time in meetings, counting overlaps once
+
+	Days  []Period // one per day of the range
+	Weeks []Period // one per week, starting on Monday
+
+	// FocusBlocks are the longest stretches of working hours without
+	// meetings, longest first
+	FocusBlocks []Slot
+
+	// BackToBack counts runs of two or more meetings with at most a few
+	// minutes between them, and LongestRun is the most meetings in one run
+	BackToBack int
+	LongestRun int
+
+	// Time spent per recurring meeting, organizer and calendar, most first
+	Recurring  []Share
+	Organizers []Share
+	Calendars  []Share
+}
+
+// Period is the time in meetings during a day or week
+type Period struct {
+	Start time.Time
+	Time  time.Duration
+}
+
+// Share is the time taken by one recurring meeting, organizer or calendar
+type Share struct {
+	Name  string
+	Time  time.Duration
+	Count int
+}
+
+// isMeeting reports whether the event counts as a meeting in stats: a timed
+// event that keeps you busy and isn't a focus time, out of office or working
+// location entry
+func (e *Event) isMeeting() bool {
+	return e.keepsBusy() && (e.EventType == "" || e.EventType == "default")
+}
+
+// ComputeStats works out the meeting load of the events in [from, to),
+// counting only the part of a meeting inside the range. Focus blocks are
+// found within working hours on weekdays, up to now.
+func ComputeStats(events []*Event, from time.Time, to time.Time, hours WorkingHours, now time.Time) Stats {
+	stats := Stats{From: from, To: to}
+
+	var meetings []*Event
+	for _, event := range events {
+		if event.isMeeting() && event.StartTime.Before(to) && event.EndTime.After(from) {
+			meetings = append(meetings, event)
+		}
+	}
+	stats.Meetings = len(meetings)
+	busy := BusyTimes(meetings)
+
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		period := Period{Start: day, Time: overlap(busy, day, day.AddDate(0, 0, 1))}
+		stats.Days = append(stats.Days, period)
+		stats.Total += period.Time
+
+		// Weeks start on Monday, or on the first day of the range
+		if n := len(stats.Weeks); n == 0 || day.Weekday() == time.Monday {
+			stats.Weeks = append(stats.Weeks, Period{Start: day})
+		}
+		stats.Weeks[len(stats.Weeks)-1].Time += period.Time
+	}
+
+	// Time that hasn't happened yet isn't focus time
+	focusEnd := to
+	if now.Before(focusEnd) {
+		focusEnd = now
+	}
+	stats.FocusBlocks = FindFreeSlots(busy, from, focusEnd, FreeSlotOptions{Hours: hours, Duration: time.Minute})
+	sort.SliceStable(stats.FocusBlocks, func(i, j int) bool {
+		return stats.FocusBlocks[i].Duration() > stats.FocusBlocks[j].Duration()
+	})
+	if len(stats.FocusBlocks) > maxStatsEntries {
+		stats.FocusBlocks = stats.FocusBlocks[:maxStatsEntries]
+	}
+
+	stats.BackToBack, stats.LongestRun = backToBackRuns(meetings)
+
+	recurring := make(map[string]*Share)
+	organizers := make(map[string]*Share)
+	calendars := make(map[string]*Share)
+	for _, event := range meetings {
+		d := overlap([]Slot{{Start: event.StartTime, End: event.EndTime}}, from, to)
+		if event.RecurringEventId != "" {
+			addShare(recurring, event.RecurringEventId, event.Summary, d)
+		}
+		organizer := organizerName(event)
+		addShare(organizers, organizer, organizer, d)
+		addShare(calendars, event.CalendarID, event.CalendarID, d)
+	}
+	stats.Recurring = topShares(recurring)
+	stats.Organizers = topShares(organizers)
+	stats.Calendars = topShares(calendars)
+	return stats
+}
+
+// overlap returns how much of [from, to) the busy slots cover
+func overlap(busy []Slot, from time.Time, to time.Time) time.Duration {
+	var total time.Duration
+	for _, slot := range busy {
+		start, end := slot.Start, slot.End
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
+
+// backToBackRuns counts the runs of back-to-back meetings and the number of
+// meetings in the longest one; meetings must be in start order
+func backToBackRuns(meetings []*Event) (int, int) {
+	runs, longest := 0, 0
+	length := 1
+	end := time.Time{}
+	for i, event := range meetings {
+		// Overlapping meetings leave no break either
+		if i > 0 && !event.StartTime.After(end.Add(backToBackGap)) {
+			length++
+		} else {
+			length = 1
+		}
+		if length == 2 {
+			runs++
+		}
+		longest = max(longest, length)
+		if event.EndTime.After(end) {
+			end = event.EndTime
+		}
+	}
+	if longest < 2 {
+		longest = 0
+	}
+	return runs, longest
+}
+
+// organizerName names who organized the event, "you" for your own events
+func organizerName(e *Event) string {
+	switch {
+	case e.Organizer == nil || e.Organizer.Self:
+		return "you"
+	case e.Organizer.DisplayName != "":
+		return e.Organizer.DisplayName
+	default:
+		return e.Organizer.Email
+	}
+}
+
+// addShare adds an event's duration to the share with the given key
+func addShare(shares map[string]*Share, key string, name string, d time.Duration) {
+	share, ok := shares[key]
+	if !ok {
+		share = &Share{Name: name}
+		shares[key] = share
+	}
+	share.Time += d
+	share.Count++
+}
+
+// topShares returns the largest shares, most time first
+func topShares(shares map[string]*Share) []Share {
+	sorted := make([]Share, 0, len(shares))
+	for _, share := range shares {
+		sorted = append(sorted, *share)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Time != sorted[j].Time {
+			return sorted[i].Time > sorted[j].Time
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	if len(sorted) > maxStatsEntries {
+		sorted = sorted[:maxStatsEntries]
+	}
+	return sorted
+}
@@ -0,0 +1,105 @@
+package calendar
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// statsEvent returns a meeting on the primary calendar between start and end
+func statsEvent(summary string, start time.Time, end time.Time) *Event {
+	return &Event{
+		Event:      &calendar.Event{Summary: summary},
+		CalendarID: "primary",
+		StartTime:  start,
+		EndTime:    end,
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	standup := statsEvent("Standup", at(0, 9, 0), at(0, 9, 30))
+	standup.RecurringEventId = "standup"
+	standup2 := statsEvent("Standup", at(1, 9, 0), at(1, 9, 30))
+	standup2.RecurringEventId = "standup"
+	review := statsEvent("Review", at(0, 9, 30), at(0, 10, 0))
+	review.Organizer = &calendar.EventOrganizer{DisplayName: "Bob"}
+	declined := statsEvent("Declined", at(0, 14, 0), at(0, 15, 0))
+	declined.Attendees = []*calendar.EventAttendee{{Self: true, ResponseStatus: "declined"}}
+	allDay := statsEvent("Holiday", at(1, 0, 0), at(2, 0, 0))
+	allDay.IsAllDay = true
+
+	events := []*Event{
+		statsEvent("Late call", at(-1, 23, 0), at(0, 1, 0)), // 1h of it in range
+		standup, review, declined, standup2, allDay,
+		statsEvent("Night call", at(1, 23, 30), at(2, 0, 30)), // 30m of it in range
+	}
+	hours := WorkingHours{Start: 9 * 60, End: 17 * 60}
+	stats := ComputeStats(events, at(0, 0, 0), at(2, 0, 0), hours, at(1, 12, 0))
+
+	if stats.Meetings != 5 {
+		t.Errorf("Meetings = %d, want 5", stats.Meetings)
+	}
+	if stats.Total != 3*time.Hour {
+		t.Errorf("Total = %v, want 3h", stats.Total)
+	}
+	wantDays := []Period{{at(0, 0, 0), 2 * time.Hour}, {at(1, 0, 0), time.Hour}}
+	if !slices.Equal(stats.Days, wantDays) {
+		t.Errorf("Days = %v, want %v", stats.Days, wantDays)
+	}
+	if len(stats.Weeks) != 1 || stats.Weeks[0].Time != 3*time.Hour {
+		t.Errorf("Weeks = %v, want one week of 3h", stats.Weeks)
+	}
+
+	// Focus time ends now, at noon on the second day
+	wantFocus := []Slot{{at(0, 10, 0), at(0, 17, 0)}, {at(1, 9, 30), at(1, 12, 0)}}
+	if !slices.Equal(stats.FocusBlocks, wantFocus) {
+		t.Errorf("FocusBlocks = %v, want %v", stats.FocusBlocks, wantFocus)
+	}
+
+	if stats.BackToBack != 1 || stats.LongestRun != 2 {
+		t.Errorf("BackToBack, LongestRun = %d, %d, want 1, 2", stats.BackToBack, stats.LongestRun)
+	}
+
+	wantRecurring := []Share{{"Standup", time.Hour, 2}}
+	if !slices.Equal(stats.Recurring, wantRecurring) {
+		t.Errorf("Recurring = %v, want %v", stats.Recurring, wantRecurring)
+	}
+	// Meetings crossing the range's edges only count their part inside it
+	wantOrganizers := []Share{{"you", 150 * time.Minute, 4}, {"Bob", 30 * time.Minute, 1}}
+	if !slices.Equal(stats.Organizers, wantOrganizers) {
+		t.Errorf("Organizers = %v, want %v", stats.Organizers, wantOrganizers)
+	}
+	wantCalendars := []Share{{"primary", 3 * time.Hour, 5}}
+	if !slices.Equal(stats.Calendars, wantCalendars) {
+		t.Errorf("Calendars = %v, want %v", stats.Calendars, wantCalendars)
+	}
+}
+
+func TestBackToBackRuns(t *testing.T) {
+	tests := []struct {
+		name          string
+		slots         []Slot
+		runs, longest int
+	}{
+		{"none", nil, 0, 0},
+		{"apart", []Slot{{at(0, 9, 0), at(0, 10, 0)}, {at(0, 10, 30), at(0, 11, 0)}}, 0, 0},
+		{"within the gap", []Slot{{at(0, 9, 0), at(0, 10, 0)}, {at(0, 10, 5), at(0, 11, 0)}}, 1, 2},
+		{"overlapping", []Slot{{at(0, 9, 0), at(0, 10, 0)}, {at(0, 9, 30), at(0, 11, 0)}, {at(0, 11, 0), at(0, 12, 0)}}, 1, 3},
+		{
+			"two runs",
+			[]Slot{{at(0, 9, 0), at(0, 10, 0)}, {at(0, 10, 0), at(0, 11, 0)}, {at(0, 14, 0), at(0, 15, 0)}, {at(0, 15, 0), at(0, 16, 0)}, {at(0, 16, 0), at(0, 17, 0)}},
+			2, 3,
+		},
+	}
+	for _, tt := range tests {
+		var meetings []*Event
+		for _, slot := range tt.slots {
+			meetings = append(meetings, statsEvent("", slot.Start, slot.End))
+		}
+		if runs, longest := backToBackRuns(meetings); runs != tt.runs || longest != tt.longest {
+			t.Errorf("%s: backToBackRuns() = %d, %d, want %d, %d", tt.name, runs, longest, tt.runs, tt.longest)
+		}
+	}
+}
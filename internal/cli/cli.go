@@ -0,0 +1,331 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"time"
+
+	gcal "google.golang.org/api/calendar/v3"
+
+	"oredavids.com/myCal/internal/auth"
+	"oredavids.com/myCal/internal/calendar"
+	"oredavids.com/myCal/internal/config"
+	"oredavids.com/myCal/internal/tui"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+// defaultCommand runs when myCal is invoked without a subcommand
+const defaultCommand = "today"
+
+// usageError marks errors caused by invalid arguments rather than failures
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// usageErrorf returns an error that makes Run exit with ExitUsage
+func usageErrorf(format string, args ...any) error {
+	return usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// Command is a myCal subcommand with its own flags
+type Command struct {
+	Name        string
+	Args        string // argument synopsis shown in help, e.g. "<shell>"
+	Summary     string
+	Subcommands []string // positional choices, used for help and completion
+	Flags       *flag.FlagSet
+	Run         func(ctx context.Context, args []string) error
+}
+
+// commonFlags are shared by every command that reads the calendar
+type commonFlags struct {
+	theme     string
+	themeName string     // resolved key in tui.Themes, set by apply
+	styles    tui.Styles // styles for the resolved theme, set by apply
+	color     string
+	tz        string
+	demo      bool
+	noBrowser bool
+}
+
+// register adds the common flags to a command's flag set
+func (c *commonFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.theme, "theme", "", "Color theme, 'auto' or '<name>:auto' (default: the saved theme; see 'myCal themes')")
+	fs.StringVar(&c.color, "color", tui.ColorAuto, "Use colors: auto, always or never (auto honors NO_COLOR and prints plain text when piped)")
+	fs.StringVar(&c.tz, "tz", "", "Show times in this IANA time zone instead of the local one, e.g. America/New_York")
+	fs.BoolVar(&c.demo, "demo", false, "Use demo data instead of your calendar (for screenshots)")
+	fs.BoolVar(&c.noBrowser, "no-browser", false, "Authorize by pasting the redirect URL instead of opening a browser (for SSH sessions)")
+}
+
+// apply checks the config file and activates the selected color mode, theme
+// and time zones
+func (c *commonFlags) apply() error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if err := tui.SetColorMode(c.color); err != nil {
+		return usageErrorf("%v", err)
+	}
+	theme := c.theme
+	if theme == "" {
+		theme = config.Get().Theme
+	}
+	if theme == "" {
+		theme = "default"
+	}
+	name, ok := tui.ResolveTheme(theme)
+	if !ok {
+		return usageErrorf("unknown theme %q (available: %s, %s)", theme, strings.Join(tui.GetThemeNames(), ", "), tui.AutoTheme)
+	}
+	c.themeName = name
+	c.styles = tui.NewStyles(tui.Themes[name])
+
+	if c.tz != "" {
+		loc, err := time.LoadLocation(c.tz)
+		if err != nil {
+			return usageErrorf("unknown time zone %q", c.tz)
+		}
+		time.Local = loc
+	}
+	if err := tui.SetTimeZones(config.Get().SecondaryTimeZones, config.Get().WorldClock); err != nil {
+		return fmt.Errorf("invalid config file: %v", err)
+	}
+	return nil
+}
+
+// service returns an authenticated calendar service
+func (c *commonFlags) service(ctx context.Context) (*gcal.Service, error) {
+	if config.GetCredsDirectory() == "" {
+		fmt.Fprintf(os.Stderr, "Credentials directory not configured. Current working directory will be used.\n Set '%s' env variable to configure\n", config.CredsDirectoryEnv)
+	}
+
+	srv, err := auth.GetCalendarService(ctx, auth.Options{NoBrowser: c.noBrowser})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar service: %w", err)
+	}
+	return srv, nil
+}
+
+// Run parses args, dispatches to the matching command and returns the exit code
+func Run(args []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Flag defaults come from the config file, but only commands that use it
+	// report a broken one, so help, completion and config path still work
+	loadConfig()
+	if err := tui.LoadUserThemes(config.GetThemesDirectory()); err != nil {
+		fmt.Fprintf(os.Stderr, "myCal: skipping invalid themes:\n%v\n", err)
+	}
+
+	commands := newCommands()
+	name, args := resolveCommand(args)
+
+	switch name {
+	case "help", "-h", "-help", "--help":
+		if len(args) > 0 {
+			if cmd := findCommand(commands, args[0]); cmd != nil {
+				cmd.Flags.SetOutput(os.Stdout)
+				cmd.Flags.Usage()
+				return ExitOK
+			}
+		}
+		printUsage(os.Stdout, commands)
+		return ExitOK
+	}
+
+	cmd := findCommand(commands, name)
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "myCal: unknown command %q\n\n", name)
+		printUsage(os.Stderr, commands)
+		return ExitUsage
+	}
+
+	if err := cmd.Flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+
+	if err := cmd.Run(ctx, cmd.Flags.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "myCal %s: %v\n", cmd.Name, err)
+		if errors.As(err, new(usageError)) {
+			return ExitUsage
+		}
+		return ExitError
+	}
+	return ExitOK
+}
+
+// configErr is the result of loading the config file, set by loadConfig
+var (
+	configLoaded bool
+	configErr    error
+)
+
+// loadConfig reads and validates the config file on first use and applies
+// its calendars, filters, auto-join rules and working hours. Later calls
+// return the first result.
+func loadConfig() error {
+	if configLoaded {
+		return configErr
+	}
+	configLoaded = true
+	configErr = applyConfig()
+	return configErr
+}
+
+// applyConfig loads the config file and hands its settings to the calendar
+// and tui packages
+func applyConfig() error {
+	if err := config.Load(); err != nil {
+		return err
+	}
+	calendar.SetCalendars(config.Get().Calendars)
+	calendar.SetLookahead(config.GetSearchDays())
+	calendar.SetFilter(newFilter(config.Get().Filters))
+	tui.AutoJoinRules = newAutoJoinRules(config.Get().AutoJoin)
+	hours, err := calendar.ParseWorkingHours(config.GetWorkingHours())
+	if err != nil {
+		return fmt.Errorf("invalid workingHours in config file: %v", err)
+	}
+	tui.FreeTimeOptions.Hours = hours
+	tui.FreeTimeOptions.Buffer = time.Duration(config.Get().BufferMinutes) * time.Minute
+	return nil
+}
+
+// newFilter converts the filter rules from the config file, whose title
+// patterns were already validated when it was loaded
+func newFilter(rules config.Filters) calendar.Filter {
+	f := calendar.Filter{
+		Calendars:   rules.Calendars,
+		EventTypes:  rules.EventTypes,
+		Declined:    rules.Declined,
+		AllDay:      rules.AllDay,
+		NoAttendees: rules.NoAttendees,
+	}
+	for _, pattern := range rules.Titles {
+		f.Titles = append(f.Titles, regexp.MustCompile(pattern))
+	}
+	return f
+}
+
+// newAutoJoinRules converts the auto-join rules from the config file, whose
+// title patterns were already validated when it was loaded
+func newAutoJoinRules(rules []config.AutoJoinRule) []tui.AutoJoinRule {
+	var joins []tui.AutoJoinRule
+	for _, rule := range rules {
+		join := tui.AutoJoinRule{
+			Calendar: rule.Calendar,
+			Lead:     time.Duration(rule.LeadSeconds) * time.Second,
+		}
+		if rule.LeadSeconds == 0 {
+			join.Lead = config.DefaultAutoJoinLead * time.Second
+		}
+		if rule.Title != "" {
+			join.Title = regexp.MustCompile(rule.Title)
+		}
+		joins = append(joins, join)
+	}
+	return joins
+}
+
+// resolveCommand splits args into a command name and its arguments. Without a
+// command the default one runs; the old --watch/-w flag still selects watch.
+func resolveCommand(args []string) (string, []string) {
+	if len(args) == 0 {
+		return defaultCommand, nil
+	}
+
+	first := args[0]
+	if !strings.HasPrefix(first, "-") {
+		return first, args[1:]
+	}
+	if first == "-h" || first == "-help" || first == "--help" {
+		return "help", args[1:]
+	}
+
+	name := defaultCommand
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		switch arg {
+		case "-w", "--w", "-watch", "--watch":
+			name = "watch"
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return name, rest
+}
+
+// findCommand returns the command with the given name, or nil
+func findCommand(commands []*Command, name string) *Command {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// newFlagSet creates a flag set whose help output matches the top-level usage
+func newFlagSet(cmd *Command) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		synopsis := "myCal " + cmd.Name
+		if hasFlags(fs) {
+			synopsis += " [flags]"
+		}
+		if cmd.Args != "" {
+			synopsis += " " + cmd.Args
+		}
+		fmt.Fprintf(out, "Usage: %s\n\n%s\n", synopsis, cmd.Summary)
+		if len(cmd.Subcommands) > 0 {
+			fmt.Fprintf(out, "\nCommands: %s\n", strings.Join(cmd.Subcommands, ", "))
+		}
+		if hasFlags(fs) {
+			fmt.Fprintf(out, "\nFlags:\n")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// hasFlags reports whether any flags are defined on fs
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
+
+// printUsage writes the top-level help
+func printUsage(w io.Writer, commands []*Command) {
+	fmt.Fprintf(w, "myCal - a terminal Google Calendar client\n\n")
+	fmt.Fprintf(w, "Usage: myCal <command> [flags] [args]\n\n")
+	fmt.Fprintf(w, "Commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", cmd.Name, cmd.Summary)
+	}
+	fmt.Fprintf(w, "  %-11s %s\n", "help", "Show help for a command")
+	fmt.Fprintf(w, "\nRun 'myCal help <command>' or 'myCal <command> --help' for command flags.\n")
+	fmt.Fprintf(w, "Without a command, myCal runs '%s'.\n", defaultCommand)
+}
@@ -0,0 +1,864 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+	gcal "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+
+	"oredavids.com/myCal/internal/auth"
+	"oredavids.com/myCal/internal/calendar"
+	"oredavids.com/myCal/internal/config"
+	"oredavids.com/myCal/internal/tui"
+)
+
+// newCommands returns every subcommand in the order shown in help
+func newCommands() []*Command {
+	return []*Command{
+		todayCommand(),
+		nextCommand(),
+		agendaCommand(),
+		searchCommand(),
+		freeCommand(),
+		scheduleCommand(),
+		statsCommand(),
+		watchCommand(),
+		statusCommand(),
+		themesCommand(),
+		authCommand(),
+		configCommand(),
+		completionCommand(),
+	}
+}
+
+func todayCommand() *Command {
+	var common commonFlags
+	var asJSON bool
+	cmd := &Command{
+		Name:    "today",
+		Summary: "Show the rest of today's events and what's coming up",
+	}
+	cmd.Flags = newFlagSet(cmd)
+	common.register(cmd.Flags)
+	cmd.Flags.BoolVar(&asJSON, "json", false, "Print the events and conflicts as JSON")
+
+	// show writes the output in the selected format
+	show := func(data tui.RenderData) error {
+		data.Conflicts = calendar.FindConflicts(data.TodayEvents)
+		if !asJSON {
+			fmt.Print(common.styles.RenderStatic(data))
+			return nil
+		}
+		output, err := tui.RenderJSON(data)
+		if err != nil {
+			return err
+		}
+		fmt.Print(output)
+		return nil
+	}
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if err := common.apply(); err != nil {
+			return err
+		}
+
+		if common.demo {
+			todayEvents, upcomingEvents, nextEvent := calendar.GetDemoEvents()
+			todayEvents, hiddenToday := calendar.Visible(todayEvents)
+			upcomingEvents, hiddenUpcoming := calendar.Visible(upcomingEvents)
+			return show(tui.RenderData{
+				UserName:       "acme-user",
+				TodayEvents:    todayEvents,
+				UpcomingEvents: upcomingEvents,
+				NextEvent:      nextEvent,
+				Hidden:         hiddenToday + hiddenUpcoming,
+			})
+		}
+
+		srv, err := common.service(ctx)
+		if err != nil {
+			return err
+		}
+
+		todayEvents, err := calendar.FetchTodayEvents(srv)
+		if err != nil {
+			return err
+		}
+		todayEvents, hidden := calendar.Visible(todayEvents)
+		nextEvent, err := calendar.FetchNextEvent(srv)
+		if err != nil {
+			return err
+		}
+
+		var upcomingEvents []*calendar.Event
+		if len(todayEvents) < 3 {
+			upcomingEvents, err = calendar.FetchUpcomingEvents(srv, 5, true)
+			if err != nil {
+				return err
+			}
+			var hiddenUpcoming int
+			upcomingEvents, hiddenUpcoming = calendar.Visible(upcomingEvents)
+			hidden += hiddenUpcoming
+		}
+
+		return show(tui.RenderData{
+			UserName:       tui.GetUserName(),
+			TodayEvents:    todayEvents,
+			UpcomingEvents: upcomingEvents,
+			NextEvent:      nextEvent,
+			Hidden:         hidden,
+		})
+	}
+	return cmd
+}
+
+func nextCommand() *Command {
+	var common commonFlags
+	cmd := &Command{
+		Name:    "next",
+		Summary: "Show the next timed event with its countdown and links",
+	}
+	cmd.Flags = newFlagSet(cmd)
+	common.register(cmd.Flags)
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if err := common.apply(); err != nil {
+			return err
+		}
+
+		var next *calendar.Event
+		if common.demo {
+			_, _, next = calendar.GetDemoEvents()
+		} else {
+			srv, err := common.service(ctx)
+			if err != nil {
+				return err
+			}
+			next, err = calendar.FetchNextEvent(srv)
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Println(common.styles.RenderNext(next))
+		return nil
+	}
+	return cmd
+}
+
+func agendaCommand() *Command {
+	var common commonFlags
+	var days int
+	var asJSON bool
+	cmd := &Command{
+		Name:    "agenda",
+		Summary: "Show events for the coming days, grouped by day",
+	}
+	cmd.Flags = newFlagSet(cmd)
+	common.register(cmd.Flags)
+	cmd.Flags.IntVar(&days, "days", 7, "Number of days to show, starting today")
+	cmd.Flags.BoolVar(&asJSON, "json", false, "Print the events and conflicts as JSON")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if err := common.apply(); err != nil {
+			return err
+		}
+		if days < 1 {
+			return usageErrorf("--days must be at least 1")
+		}
+
+		now := time.Now()
+		from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		to := from.AddDate(0, 0, days)
+
+		var events []*calendar.Event
+		if common.demo {
+			today, upcoming, _ := calendar.GetDemoEvents()
+			events = append(today, upcoming...)
+		} else {
+			srv, err := common.service(ctx)
+			if err != nil {
+				return err
+			}
+			events, err = calendar.FetchEventsBetween(srv, from, to)
+			if err != nil {
+				return err
+			}
+		}
+
+		events, hidden := calendar.Visible(events)
+		if asJSON {
+			output, err := tui.RenderAgendaJSON(events, hidden)
+			if err != nil {
+				return err
+			}
+			fmt.Print(output)
+			return nil
+		}
+		fmt.Print(common.styles.RenderAgenda(events, from, days))
+		if hidden > 0 {
+			fmt.Println(common.styles.RenderHiddenCount(hidden))
+		}
+		return nil
+	}
+	return cmd
+}
+
+func searchCommand() *Command {
+	var common commonFlags
+	var days int
+	cmd := &Command{
+		Name:    "search",
+		Args:    "<query>",
+		Summary: "Find events by title, location, description or attendees",
+	}
+	cmd.Flags = newFlagSet(cmd)
+	common.register(cmd.Flags)
+	cmd.Flags.IntVar(&days, "days", config.GetSearchDays(), "Number of days to search, starting today")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		// Allow flags between and after the query words
+		var words []string
+		for len(args) > 0 {
+			if strings.HasPrefix(args[0], "-") {
+				if err := cmd.Flags.Parse(args); errors.Is(err, flag.ErrHelp) {
+					return nil
+				} else if err != nil {
+					return usageErrorf("%v", err)
+				}
+				args = cmd.Flags.Args()
+				continue
+			}
+			words = append(words, args[0])
+			args = args[1:]
+		}
+		query := strings.Join(words, " ")
+		if query == "" {
+			return usageErrorf("missing search query")
+		}
+
+		if err := common.apply(); err != nil {
+			return err
+		}
+		if days < 1 {
+			return usageErrorf("--days must be at least 1")
+		}
+
+		now := time.Now()
+		from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		to := from.AddDate(0, 0, days)
+
+		var events []*calendar.Event
+		if common.demo {
+			today, upcoming, _ := calendar.GetDemoEvents()
+			events = calendar.FilterEvents(append(today, upcoming...), query)
+		} else {
+			srv, err := common.service(ctx)
+			if err != nil {
+				return err
+			}
+			events, err = calendar.SearchEvents(srv, query, from, to)
+			if err != nil {
+				return err
+			}
+		}
+
+		events, hidden := calendar.Visible(events)
+		if len(events) == 0 {
+			fmt.Printf("No events matching %q in the next %d days\n", query, days)
+		} else {
+			fmt.Print(common.styles.WithHighlight(query).RenderAgenda(events, from, days))
+		}
+		if hidden > 0 {
+			fmt.Println(common.styles.RenderHiddenCount(hidden))
+		}
+		return nil
+	}
+	return cmd
+}
+
+func freeCommand() *Command {
+	var common commonFlags
+	var within, workingHours string
+	var duration, buffer time.Duration
+	var weekends bool
+	cmd := &Command{
+		Name:    "free",
+		Summary: "Find open slots in your working hours across your calendars",
+	}
+	cmd.Flags = newFlagSet(cmd)
+	common.register(cmd.Flags)
+	cmd.Flags.StringVar(&within, "within", "this week", "Range to search: today, tomorrow, this/next week or month, or e.g. 3d, 2w")
+	cmd.Flags.DurationVar(&duration, "duration", 30*time.Minute, "Shortest slot to show, e.g. 30m or 1h30m")
+	cmd.Flags.StringVar(&workingHours, "working-hours", config.GetWorkingHours(), "Hours to look in each day, e.g. 09:00-17:30")
+	cmd.Flags.DurationVar(&buffer, "buffer", time.Duration(config.Get().BufferMinutes)*time.Minute, "Time to keep free before and after meetings, e.g. 10m")
+	cmd.Flags.BoolVar(&weekends, "weekends", false, "Also look on Saturdays and Sundays")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if err := common.apply(); err != nil {
+			return err
+		}
+		if duration <= 0 {
+			return usageErrorf("--duration must be positive")
+		}
+		if buffer < 0 {
+			return usageErrorf("--buffer must not be negative")
+		}
+		hours, err := calendar.ParseWorkingHours(workingHours)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		from, to, err := calendar.ParseRange(within, time.Now())
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		// Time that has already passed isn't free
+		if now := time.Now(); from.Before(now) {
+			from = now
+		}
+		if !to.After(from) {
+			return usageErrorf("%q is already over", within)
+		}
+
+		var busy []calendar.Slot
+		if common.demo {
+			today, upcoming, _ := calendar.GetDemoEvents()
+			busy = calendar.BusyTimes(append(today, upcoming...))
+		} else {
+			srv, err := common.service(ctx)
+			if err != nil {
+				return err
+			}
+			busy, err = calendar.FetchOwnBusy(srv, from, to)
+			if err != nil {
+				return err
+			}
+		}
+
+		slots := calendar.FindFreeSlots(busy, from, to, calendar.FreeSlotOptions{
+			Hours:    hours,
+			Duration: duration,
+			Buffer:   buffer,
+			Weekends: weekends,
+		})
+		fmt.Print(common.styles.RenderFreeSlots(slots, from, to))
+		return nil
+	}
+	return cmd
+}
+
+// scheduleStep is how far apart the meeting times schedule considers are
+const scheduleStep = 30 * time.Minute
+
+func scheduleCommand() *Command {
+	var common commonFlags
+	var with, within, workingHours, title string
+	var duration time.Duration
+	var top, book int
+	var weekends, addMeet bool
+	cmd := &Command{
+		Name:    "schedule",
+		Summary: "Find a time that suits you and your colleagues, and send the invite",
+	}
+	cmd.Flags = newFlagSet(cmd)
+	common.register(cmd.Flags)
+	cmd.Flags.StringVar(&with, "with", "", "Comma-separated email addresses of the people to meet")
+	cmd.Flags.DurationVar(&duration, "duration", 30*time.Minute, "Length of the meeting, e.g. 45m")
+	cmd.Flags.StringVar(&within, "within", "7d", "Range to search: today, tomorrow, this/next week or month, or e.g. 3d, 2w")
+	cmd.Flags.StringVar(&workingHours, "working-hours", config.GetWorkingHours(), "Hours the meeting may take place in each day, e.g. 09:00-17:30")
+	cmd.Flags.BoolVar(&weekends, "weekends", false, "Also consider Saturdays and Sundays")
+	cmd.Flags.IntVar(&top, "top", 5, "Number of suggested times to show")
+	cmd.Flags.IntVar(&book, "book", 0, "Send the invite for this suggestion without asking")
+	cmd.Flags.StringVar(&title, "title", "", "Title of the invite (default: \"Meeting with\" and the names)")
+	cmd.Flags.BoolVar(&addMeet, "meet", false, "Add a Google Meet link to the invite")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if err := common.apply(); err != nil {
+			return err
+		}
+
+		var emails []string
+		for _, email := range strings.Split(with, ",") {
+			if email = strings.TrimSpace(email); email != "" {
+				emails = append(emails, email)
+			}
+		}
+		if len(emails) == 0 {
+			return usageErrorf("--with needs at least one email address")
+		}
+		if duration <= 0 {
+			return usageErrorf("--duration must be positive")
+		}
+		if top < 1 {
+			return usageErrorf("--top must be at least 1")
+		}
+		if book < 0 || book > top {
+			return usageErrorf("--book must be between 1 and %d", top)
+		}
+		if book > 0 && common.demo {
+			return usageErrorf("--book does not work with --demo")
+		}
+		hours, err := calendar.ParseWorkingHours(workingHours)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		from, to, err := calendar.ParseRange(within, time.Now())
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		if now := time.Now(); from.Before(now) {
+			from = now
+		}
+		if !to.After(from) {
+			return usageErrorf("%q is already over", within)
+		}
+
+		var srv *gcal.Service
+		var participants []calendar.Participant
+		if common.demo {
+			participants = calendar.GetDemoParticipants(emails, from)
+		} else {
+			srv, err = common.service(ctx)
+			if err != nil {
+				return err
+			}
+			participants, err = calendar.FetchParticipants(srv, emails, from, to)
+			if err != nil {
+				return err
+			}
+		}
+
+		opts := calendar.FreeSlotOptions{Hours: hours, Duration: duration, Weekends: weekends}
+		candidates := calendar.RankCandidates(participants, from, to, opts, scheduleStep)
+		if len(candidates) > top {
+			candidates = candidates[:top]
+		}
+
+		styles := common.styles
+		fmt.Println(styles.RenderParticipants(participants))
+		fmt.Println()
+		fmt.Print(styles.RenderHeatmap(participants, from, to, opts, scheduleStep))
+		fmt.Println()
+		fmt.Print(styles.RenderCandidates(candidates))
+
+		if len(candidates) == 0 || common.demo {
+			return nil
+		}
+		if book == 0 {
+			book, err = askForSuggestion(len(candidates))
+			if err != nil || book == 0 {
+				return err
+			}
+		}
+		if book > len(candidates) {
+			return usageErrorf("there are only %d suggestions", len(candidates))
+		}
+
+		if title == "" {
+			title = "Meeting with " + strings.Join(emails, ", ")
+		}
+		chosen := candidates[book-1]
+		event, err := calendar.CreateMeeting(srv, title, chosen.Slot, emails, addMeet)
+		if err != nil {
+			var apiErr *googleapi.Error
+			if errors.As(err, &apiErr) && apiErr.Code == 403 {
+				return fmt.Errorf("%v\nmyCal can only read your calendar; run 'myCal auth login --scope calendar.events' to let it send invites", err)
+			}
+			return err
+		}
+
+		fmt.Printf("\nInvited %s to %q on %s %s\n", strings.Join(emails, ", "), title,
+			chosen.Start.Format("Mon Jan 2"), tui.FormatTimeRange(chosen.Start, chosen.End))
+		if event.MeetingURL != "" {
+			fmt.Println(event.MeetingURL)
+		}
+		if event.HtmlLink != "" {
+			fmt.Println(event.HtmlLink)
+		}
+		return nil
+	}
+	return cmd
+}
+
+// askForSuggestion asks which of n suggestions to send an invite for, and
+// returns 0 when the answer is empty or nobody is there to answer
+func askForSuggestion(n int) (int, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return 0, nil
+	}
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Printf("\nSend an invite for which time? (1-%d, Enter to skip) ", n)
+		line, err := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return 0, nil
+		}
+		if choice, convErr := strconv.Atoi(line); convErr == nil && choice >= 1 && choice <= n {
+			return choice, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("unable to read choice: %v", err)
+		}
+		fmt.Printf("Please enter a number from 1 to %d.\n", n)
+	}
+}
+
+func statsCommand() *Command {
+	var common commonFlags
+	var within, workingHours string
+	var asJSON, asCSV bool
+	cmd := &Command{
+		Name:    "stats",
+		Summary: "Show how much of your time goes to meetings",
+	}
+	cmd.Flags = newFlagSet(cmd)
+	common.register(cmd.Flags)
+	cmd.Flags.StringVar(&within, "range", "last-30d", "Range to report on: today, this/last week or month, or e.g. last-30d, 2w")
+	cmd.Flags.StringVar(&workingHours, "working-hours", config.GetWorkingHours(), "Hours to look for focus blocks in each day, e.g. 09:00-17:30")
+	cmd.Flags.BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	cmd.Flags.BoolVar(&asCSV, "csv", false, "Print the report as CSV")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if err := common.apply(); err != nil {
+			return err
+		}
+		if asJSON && asCSV {
+			return usageErrorf("--json and --csv can't be used together")
+		}
+		hours, err := calendar.ParseWorkingHours(workingHours)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		from, to, err := calendar.ParseRange(within, time.Now())
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+
+		var events []*calendar.Event
+		if common.demo {
+			today, upcoming, _ := calendar.GetDemoEvents()
+			events = append(today, upcoming...)
+		} else {
+			srv, err := common.service(ctx)
+			if err != nil {
+				return err
+			}
+			events, err = calendar.FetchEventsBetween(srv, from, to)
+			if err != nil {
+				return err
+			}
+		}
+
+		stats := calendar.ComputeStats(events, from, to, hours, time.Now())
+		switch {
+		case asJSON:
+			output, err := tui.RenderStatsJSON(stats)
+			if err != nil {
+				return err
+			}
+			fmt.Print(output)
+		case asCSV:
+			output, err := tui.RenderStatsCSV(stats)
+			if err != nil {
+				return err
+			}
+			fmt.Print(output)
+		default:
+			fmt.Print(common.styles.RenderStats(stats))
+		}
+		return nil
+	}
+	return cmd
+}
+
+func watchCommand() *Command {
+	var common commonFlags
+	cmd := &Command{
+		Name:    "watch",
+		Summary: "Interactive view that refreshes automatically",
+	}
+	cmd.Flags = newFlagSet(cmd)
+	common.register(cmd.Flags)
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if err := common.apply(); err != nil {
+			return err
+		}
+		if common.demo {
+			return usageErrorf("watch does not support --demo")
+		}
+
+		srv, err := common.service(ctx)
+		if err != nil {
+			return err
+		}
+		if err := tui.Run(srv, common.themeName); err != nil {
+			return fmt.Errorf("error running TUI: %w", err)
+		}
+		return nil
+	}
+	return cmd
+}
+
+func statusCommand() *Command {
+	var common commonFlags
+	cmd := &Command{
+		Name:    "status",
+		Summary: "Print a one-line summary of the next meeting (for prompts and status bars)",
+	}
+	cmd.Flags = newFlagSet(cmd)
+	common.register(cmd.Flags)
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if err := common.apply(); err != nil {
+			return err
+		}
+
+		var next *calendar.Event
+		if common.demo {
+			_, _, next = calendar.GetDemoEvents()
+		} else {
+			srv, err := common.service(ctx)
+			if err != nil {
+				return err
+			}
+			next, err = calendar.FetchNextEvent(srv)
+			if err != nil {
+				return err
+			}
+		}
+
+		if next == nil {
+			fmt.Println("No upcoming meetings")
+			return nil
+		}
+		fmt.Printf("%s %s\n", next.Summary, tui.FormatDuration(next.TimeUntilStart()))
+		return nil
+	}
+	return cmd
+}
+
+func themesCommand() *Command {
+	cmd := &Command{
+		Name:    "themes",
+		Summary: "List built-in and user color themes with a preview",
+	}
+	var color string
+	cmd.Flags = newFlagSet(cmd)
+	cmd.Flags.StringVar(&color, "color", tui.ColorAuto, "Use colors: auto, always or never")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if err := tui.SetColorMode(color); err != nil {
+			return usageErrorf("%v", err)
+		}
+
+		var builtIn, user []string
+		for _, name := range tui.GetThemeNames() {
+			if tui.Themes[name].IsBuiltIn() {
+				builtIn = append(builtIn, name)
+			} else {
+				user = append(user, name)
+			}
+		}
+
+		fmt.Println("Built-in themes:")
+		for _, name := range builtIn {
+			fmt.Printf("  %s  %-18s %s\n", tui.RenderSwatch(tui.Themes[name]), name, tui.Themes[name].Name)
+		}
+
+		fmt.Printf("\nUser themes (%s):\n", config.GetThemesDirectory())
+		if len(user) == 0 {
+			fmt.Println("  none")
+		}
+		for _, name := range user {
+			fmt.Printf("  %s  %-18s %s\n", tui.RenderSwatch(tui.Themes[name]), name, tui.Themes[name].Name)
+		}
+		return nil
+	}
+	return cmd
+}
+
+func authCommand() *Command {
+	var noBrowser bool
+	var scopes stringList
+	cmd := &Command{
+		Name:        "auth",
+		Args:        "<command>",
+		Summary:     "Manage authorization with Google",
+		Subcommands: []string{"status", "login", "logout", "revoke"},
+	}
+	cmd.Flags = newFlagSet(cmd)
+	cmd.Flags.BoolVar(&noBrowser, "no-browser", false, "login: paste the redirect URL instead of opening a browser")
+	cmd.Flags.Var(&scopes, "scope", "login: extra OAuth scope to request, e.g. calendar.events (repeatable)")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if len(args) == 0 {
+			return usageErrorf("missing command (want %s)", strings.Join(cmd.Subcommands, ", "))
+		}
+		if err := loadConfig(); err != nil {
+			return err
+		}
+
+		// Allow flags after the subcommand too, e.g. "auth login --scope x"
+		if err := cmd.Flags.Parse(args[1:]); errors.Is(err, flag.ErrHelp) {
+			return nil
+		} else if err != nil {
+			return usageErrorf("%v", err)
+		}
+
+		switch args[0] {
+		case "status":
+			status, err := auth.Status(ctx)
+			if errors.Is(err, auth.ErrNotLoggedIn) {
+				fmt.Printf("Not logged in (no token at %s)\nRun 'myCal auth login' to authorize.\n", status.TokenPath)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			printAuthStatus(status)
+
+		case "login":
+			if err := auth.Login(ctx, auth.Options{NoBrowser: noBrowser}, scopes); err != nil {
+				return err
+			}
+			fmt.Println("Logged in.")
+
+		case "logout":
+			if err := auth.Logout(); errors.Is(err, auth.ErrNotLoggedIn) {
+				fmt.Println("Already logged out.")
+			} else if err != nil {
+				return err
+			} else {
+				fmt.Println("Deleted local token.")
+			}
+
+		case "revoke":
+			if err := auth.Revoke(ctx); errors.Is(err, auth.ErrNotLoggedIn) {
+				fmt.Println("Not logged in, nothing to revoke.")
+			} else if err != nil {
+				return err
+			} else {
+				fmt.Println("Revoked access and deleted local token.")
+			}
+
+		default:
+			return usageErrorf("unknown command %q (want %s)", args[0], strings.Join(cmd.Subcommands, ", "))
+		}
+		return nil
+	}
+	return cmd
+}
+
+func configCommand() *Command {
+	cmd := &Command{
+		Name:        "config",
+		Args:        "[command]",
+		Summary:     "Show the config file location and current settings",
+		Subcommands: []string{"show", "path"},
+	}
+	cmd.Flags = newFlagSet(cmd)
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		sub := "show"
+		if len(args) > 0 {
+			sub = args[0]
+		}
+
+		switch sub {
+		case "path":
+			fmt.Println(config.GetConfigPath())
+
+		case "show":
+			if err := loadConfig(); err != nil {
+				return err
+			}
+			b, err := json.MarshalIndent(config.Get(), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("# %s", config.GetConfigPath())
+			if _, err := os.Stat(config.GetConfigPath()); err != nil {
+				fmt.Print(" (not found, showing defaults)")
+			}
+			fmt.Printf("\n%s\n", b)
+
+		default:
+			return usageErrorf("unknown command %q (want %s)", sub, strings.Join(cmd.Subcommands, ", "))
+		}
+		return nil
+	}
+	return cmd
+}
+
+func completionCommand() *Command {
+	cmd := &Command{
+		Name:        "completion",
+		Args:        "<shell>",
+		Summary:     "Generate a shell completion script",
+		Subcommands: []string{"bash", "zsh", "fish"},
+	}
+	cmd.Flags = newFlagSet(cmd)
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return usageErrorf("expected one shell (want %s)", strings.Join(cmd.Subcommands, ", "))
+		}
+
+		commands := newCommands()
+		switch args[0] {
+		case "bash":
+			fmt.Print(bashCompletion(commands))
+		case "zsh":
+			fmt.Print(zshCompletion(commands))
+		case "fish":
+			fmt.Print(fishCompletion(commands))
+		default:
+			return usageErrorf("unsupported shell %q (want %s)", args[0], strings.Join(cmd.Subcommands, ", "))
+		}
+		return nil
+	}
+	return cmd
+}
+
+func printAuthStatus(status *auth.TokenStatus) {
+	fmt.Printf("Mode:        %s\n", status.Mode)
+	if status.Email != "" {
+		fmt.Printf("Account:     %s\n", status.Email)
+	}
+	if status.Impersonating != "" {
+		fmt.Printf("Acting as:   %s\n", status.Impersonating)
+	}
+	fmt.Printf("Scopes:      %s\n", strings.Join(status.Scopes, "\n             "))
+	if !status.Expiry.IsZero() {
+		fmt.Printf("Expires:     %s (%s)\n", status.Expiry.Local().Format("Jan 2 3:04 PM"), tui.FormatDuration(time.Until(status.Expiry)))
+		fmt.Printf("Refreshable: %t\n", status.HasRefreshToken)
+	}
+	fmt.Printf("Credentials: %s\n", status.CredentialsPath)
+	if status.TokenPath != "" {
+		fmt.Printf("Token:       %s\n", status.TokenPath)
+	}
+}
+
+// stringList is a flag.Value collecting repeated string flags
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"oredavids.com/myCal/internal/tui"
+)
+
+// completionFlag describes a flag for completion scripts
+type completionFlag struct {
+	name      string
+	usage     string
+	takesArgs bool
+}
+
+// completionFlags lists a command's flags in definition order
+func completionFlags(cmd *Command) []completionFlag {
+	var flags []completionFlag
+	cmd.Flags.VisitAll(func(f *flag.Flag) {
+		isBool := false
+		if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok {
+			isBool = bf.IsBoolFlag()
+		}
+		flags = append(flags, completionFlag{name: f.Name, usage: f.Usage, takesArgs: !isBool})
+	})
+	return flags
+}
+
+// themeChoices returns every value accepted by --theme
+func themeChoices() []string {
+	return append(tui.GetThemeNames(), tui.AutoTheme)
+}
+
+// colorChoices returns every value accepted by --color
+func colorChoices() []string {
+	return []string{tui.ColorAuto, tui.ColorAlways, tui.ColorNever}
+}
+
+// commandNames returns the names of all commands plus "help"
+func commandNames(commands []*Command) []string {
+	names := make([]string, 0, len(commands)+1)
+	for _, cmd := range commands {
+		names = append(names, cmd.Name)
+	}
+	return append(names, "help")
+}
+
+// bashCompletion generates a bash completion script
+func bashCompletion(commands []*Command) string {
+	var b strings.Builder
+
+	b.WriteString("# bash completion for myCal\n")
+	b.WriteString("# Install: myCal completion bash > /etc/bash_completion.d/myCal\n")
+	b.WriteString("_myCal() {\n")
+	b.WriteString("    local cur prev words\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+	fmt.Fprintf(&b, "    if [[ $COMP_CWORD -eq 1 ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=( $(compgen -W %q -- \"$cur\") )\n", strings.Join(commandNames(commands), " "))
+	b.WriteString("        return\n    fi\n\n")
+	fmt.Fprintf(&b, "    if [[ \"$prev\" == \"--theme\" || \"$prev\" == \"-theme\" ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=( $(compgen -W %q -- \"$cur\") )\n", strings.Join(themeChoices(), " "))
+	b.WriteString("        return\n    fi\n\n")
+	fmt.Fprintf(&b, "    if [[ \"$prev\" == \"--color\" || \"$prev\" == \"-color\" ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=( $(compgen -W %q -- \"$cur\") )\n", strings.Join(colorChoices(), " "))
+	b.WriteString("        return\n    fi\n\n")
+	b.WriteString("    case \"${COMP_WORDS[1]}\" in\n")
+	for _, cmd := range commands {
+		words := append([]string{}, cmd.Subcommands...)
+		for _, f := range completionFlags(cmd) {
+			words = append(words, "--"+f.name)
+		}
+		fmt.Fprintf(&b, "        %s) words=%q ;;\n", cmd.Name, strings.Join(words, " "))
+	}
+	fmt.Fprintf(&b, "        help) words=%q ;;\n", strings.Join(commandNames(commands), " "))
+	b.WriteString("        *) words=\"\" ;;\n")
+	b.WriteString("    esac\n")
+	b.WriteString("    COMPREPLY=( $(compgen -W \"$words\" -- \"$cur\") )\n")
+	b.WriteString("}\n")
+	b.WriteString("complete -F _myCal myCal\n")
+
+	return b.String()
+}
+
+// zshCompletion generates a zsh completion script
+func zshCompletion(commands []*Command) string {
+	var b strings.Builder
+
+	b.WriteString("#compdef myCal\n")
+	b.WriteString("# Install: myCal completion zsh > \"${fpath[1]}/_myCal\"\n\n")
+	b.WriteString("_myCal() {\n")
+	b.WriteString("    local -a commands\n")
+	b.WriteString("    commands=(\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "        %s\n", zshQuote(cmd.Name+":"+cmd.Summary))
+	}
+	fmt.Fprintf(&b, "        %s\n", zshQuote("help:Show help for a command"))
+	b.WriteString("    )\n\n")
+	b.WriteString("    if (( CURRENT == 2 )); then\n")
+	b.WriteString("        _describe 'command' commands\n")
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n\n")
+	b.WriteString("    shift words\n")
+	b.WriteString("    (( CURRENT-- ))\n\n")
+	b.WriteString("    case $words[1] in\n")
+	themes := strings.Join(themeChoices(), " ")
+	colorModes := strings.Join(colorChoices(), " ")
+	for _, cmd := range commands {
+		var specs []string
+		for _, f := range completionFlags(cmd) {
+			spec := "--" + f.name + "[" + zshEscapeBrackets(f.usage) + "]"
+			if f.name == "theme" {
+				spec = "--" + f.name + "=[" + zshEscapeBrackets(f.usage) + "]:theme:(" + themes + ")"
+			} else if f.name == "color" {
+				spec = "--" + f.name + "=[" + zshEscapeBrackets(f.usage) + "]:mode:(" + colorModes + ")"
+			} else if f.takesArgs {
+				spec = "--" + f.name + "=[" + zshEscapeBrackets(f.usage) + "]:value:"
+			}
+			specs = append(specs, zshQuote(spec))
+		}
+		if len(cmd.Subcommands) > 0 {
+			specs = append(specs, zshQuote("1:command:("+strings.Join(cmd.Subcommands, " ")+")"))
+		}
+		fmt.Fprintf(&b, "        %s)\n            _arguments %s\n            ;;\n", cmd.Name, strings.Join(specs, " \\\n                "))
+	}
+	b.WriteString("        help)\n            _describe 'command' commands\n            ;;\n")
+	b.WriteString("    esac\n")
+	b.WriteString("}\n\n")
+	b.WriteString("compdef _myCal myCal\n")
+
+	return b.String()
+}
+
+// fishCompletion generates a fish completion script
+func fishCompletion(commands []*Command) string {
+	var b strings.Builder
+
+	b.WriteString("# fish completion for myCal\n")
+	b.WriteString("# Install: myCal completion fish > ~/.config/fish/completions/myCal.fish\n")
+	b.WriteString("complete -c myCal -f\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "complete -c myCal -n __fish_use_subcommand -a %s -d %s\n", cmd.Name, fishQuote(cmd.Summary))
+	}
+	fmt.Fprintf(&b, "complete -c myCal -n __fish_use_subcommand -a help -d %s\n", fishQuote("Show help for a command"))
+
+	themes := strings.Join(themeChoices(), " ")
+	for _, cmd := range commands {
+		cond := fishQuote("__fish_seen_subcommand_from " + cmd.Name)
+		for _, sub := range cmd.Subcommands {
+			fmt.Fprintf(&b, "complete -c myCal -n %s -a %s\n", cond, sub)
+		}
+		for _, f := range completionFlags(cmd) {
+			line := fmt.Sprintf("complete -c myCal -n %s -l %s -d %s", cond, f.name, fishQuote(f.usage))
+			if f.name == "theme" {
+				line += " -xa " + fishQuote(themes)
+			} else if f.name == "color" {
+				line += " -xa " + fishQuote(strings.Join(colorChoices(), " "))
+			} else if f.takesArgs {
+				line += " -x"
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+	fmt.Fprintf(&b, "complete -c myCal -n %s -a %s\n", fishQuote("__fish_seen_subcommand_from help"), fishQuote(strings.Join(commandNames(commands), " ")))
+
+	return b.String()
+}
+
+// zshQuote single-quotes s for zsh
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// zshEscapeBrackets escapes characters that end an _arguments description
+func zshEscapeBrackets(s string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`, ":", `\:`).Replace(s)
+}
+
+// fishQuote single-quotes s for fish
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
@@ -1,21 +1,222 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path"
+	"regexp"
 
 	"github.com/joho/godotenv"
 )
 
 const CredsDirectoryEnv = "MYCAL_CREDENTIALS_DIRECTORY"
 
+// Launchers accepted in the config file besides shell commands
+const (
+	LaunchBrowser = "browser"
+	LaunchNative  = "native"
+)
+
+// Auth modes accepted in the config file
+const (
+	AuthModeAuto           = "auto"
+	AuthModeOAuth          = "oauth"
+	AuthModeServiceAccount = "service-account"
+)
+
+// DefaultSearchDays is how far ahead search looks when searchDays is unset
+const DefaultSearchDays = 30
+
+// DefaultWorkingHours bounds the free time free looks for when workingHours
+// is unset
+const DefaultWorkingHours = "09:00-17:00"
+
+// Config holds the settings read from the config file
+type Config struct {
+	// AuthMode selects how to authenticate: "auto" detects it from the
+	// credentials file, "oauth" or "service-account" force one
+	AuthMode string `json:"authMode,omitempty"`
+
+	// ImpersonateUser is the user a service account acts as through
+	// domain-wide delegation; empty uses the service account itself
+	ImpersonateUser string `json:"impersonateUser,omitempty"`
+
+	// Calendars lists the calendar IDs to read; empty means "primary"
+	Calendars []string `json:"calendars,omitempty"`
+
+	// Theme is the color theme used when --theme is not given
+	Theme string `json:"theme,omitempty"`
+
+	// SearchDays is how many days ahead, starting today, search looks; 0
+	// means DefaultSearchDays
+	SearchDays int `json:"searchDays,omitempty"`
+
+	// SecondaryTimeZones are IANA zones shown beside each event time
+	SecondaryTimeZones []string `json:"secondaryTimeZones,omitempty"`
+
+	// WorldClock lists IANA zones, optionally labelled ("Ana=Europe/Lisbon"),
+	// whose current time is shown in the header
+	WorldClock []string `json:"worldClock,omitempty"`
+
+	// Launchers choose how meetings are joined, keyed by provider ("Zoom",
+	// "Teams", ...) or "default": "browser", "native" for the provider's
+	// desktop app, or a shell command with {url}, {native} and {id}
+	// placeholders, which are replaced with quoted values and so must not be
+	// quoted again
+	Launchers map[string]string `json:"launchers,omitempty"`
+
+	// WorkingHours bounds the free time free looks for, e.g. "09:00-17:30";
+	// empty means DefaultWorkingHours
+	WorkingHours string `json:"workingHours,omitempty"`
+
+	// BufferMinutes is the time free keeps open before and after meetings
+	BufferMinutes int `json:"bufferMinutes,omitempty"`
+
+	// AutoJoin opens matching meetings automatically in watch mode
+	AutoJoin []AutoJoinRule `json:"autoJoin,omitempty"`
+
+	// Filters hides noisy events from every view
+	Filters Filters `json:"filters,omitzero"`
+}
+
+// DefaultAutoJoinLead is how many seconds before the start a meeting is
+// auto-joined when leadSeconds is unset
+const DefaultAutoJoinLead = 60
+
+// AutoJoinRule selects meetings to join automatically. An event matches when
+// it matches every field that is set; a rule without any matches all events.
+type AutoJoinRule struct {
+	// Title is a regular expression matched against event titles
+	Title string `json:"title,omitempty"`
+
+	// Calendar is the calendar ID the event must come from
+	Calendar string `json:"calendar,omitempty"`
+
+	// LeadSeconds is how long before the start to join; 0 means
+	// DefaultAutoJoinLead
+	LeadSeconds int `json:"leadSeconds,omitempty"`
+}
+
+// Filters lists the rules for hiding events; an event matching any rule is
+// hidden
+type Filters struct {
+	// Titles are regular expressions matched against event titles
+	Titles []string `json:"titles,omitempty"`
+
+	// Calendars are calendar IDs whose events are hidden
+	Calendars []string `json:"calendars,omitempty"`
+
+	// EventTypes are Google Calendar event types, e.g. "focusTime",
+	// "outOfOffice" or "workingLocation"
+	EventTypes []string `json:"eventTypes,omitempty"`
+
+	// Declined hides events you declined
+	Declined bool `json:"declined,omitempty"`
+
+	// AllDay hides all-day events
+	AllDay bool `json:"allDay,omitempty"`
+
+	// NoAttendees hides events without any other attendees
+	NoAttendees bool `json:"noAttendees,omitempty"`
+}
+
 var credsDirectory string
 
+var current = Config{AuthMode: AuthModeAuto}
+
 func init() {
 	godotenv.Load()
 	credsDirectory = os.Getenv(CredsDirectoryEnv)
 }
 
+// Load reads the config file if it exists. A missing file is not an error.
+func Load() error {
+	b, err := os.ReadFile(GetConfigPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read config file: %v", err)
+	}
+
+	cfg := Config{AuthMode: AuthModeAuto}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return fmt.Errorf("unable to parse config file %s: %v", GetConfigPath(), err)
+	}
+
+	switch cfg.AuthMode {
+	case "":
+		cfg.AuthMode = AuthModeAuto
+	case AuthModeAuto, AuthModeOAuth, AuthModeServiceAccount:
+	default:
+		return fmt.Errorf("invalid authMode %q in config file (want %s, %s or %s)",
+			cfg.AuthMode, AuthModeAuto, AuthModeOAuth, AuthModeServiceAccount)
+	}
+
+	for _, pattern := range cfg.Filters.Titles {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid title filter in config file: %v", err)
+		}
+	}
+
+	for _, rule := range cfg.AutoJoin {
+		if _, err := regexp.Compile(rule.Title); err != nil {
+			return fmt.Errorf("invalid auto-join title in config file: %v", err)
+		}
+		if rule.LeadSeconds < 0 {
+			return fmt.Errorf("invalid auto-join leadSeconds %d in config file (want a positive number of seconds)", rule.LeadSeconds)
+		}
+	}
+
+	if cfg.SearchDays < 0 {
+		return fmt.Errorf("invalid searchDays %d in config file (want a positive number of days)", cfg.SearchDays)
+	}
+
+	if cfg.BufferMinutes < 0 {
+		return fmt.Errorf("invalid bufferMinutes %d in config file (want a positive number of minutes)", cfg.BufferMinutes)
+	}
+
+	current = cfg
+	return nil
+}
+
+// Save writes cfg to the config file and makes it the current configuration
+func Save(cfg Config) error {
+	b, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(GetConfigPath(), append(b, '\n'), 0600); err != nil {
+		return fmt.Errorf("unable to write config file: %v", err)
+	}
+	current = cfg
+	return nil
+}
+
+// Get returns the loaded configuration
+func Get() Config {
+	return current
+}
+
+// GetSearchDays returns how many days ahead search looks
+func GetSearchDays() int {
+	if current.SearchDays == 0 {
+		return DefaultSearchDays
+	}
+	return current.SearchDays
+}
+
+// GetWorkingHours returns the working hours free time is looked for in
+func GetWorkingHours() string {
+	if current.WorkingHours == "" {
+		return DefaultWorkingHours
+	}
+	return current.WorkingHours
+}
+
 // GetCredsDirectory returns the configured credentials directory
 func GetCredsDirectory() string {
 	return credsDirectory
@@ -30,3 +231,13 @@ func GetCredentialsPath() string {
 func GetTokenPath() string {
 	return path.Join(credsDirectory, "myCalAppToken.json")
 }
+
+// GetThemesDirectory returns the directory user theme files are loaded from
+func GetThemesDirectory() string {
+	return path.Join(credsDirectory, "themes")
+}
+
+// GetConfigPath returns the full path to the config file
+func GetConfigPath() string {
+	return path.Join(credsDirectory, "myCalConfig.json")
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"oredavids.com/myCal/internal/calendar"
+)
+
+// AutoJoinRule selects meetings watch mode opens automatically
+type AutoJoinRule struct {
+	Title    *regexp.Regexp // nil matches any title
+	Calendar string         // "" matches any calendar
+	Lead     time.Duration  // how long before the start to join
+}
+
+// AutoJoinRules are checked in order; the first match decides the lead time
+var AutoJoinRules []AutoJoinRule
+
+// autoJoinWarning is how long the cancellable banner shows before joining
+const autoJoinWarning = 10 * time.Second
+
+// autoJoinLead returns how long before its start the event should be joined,
+// and false if it shouldn't be. Declined events and events without other
+// attendees are never joined.
+func autoJoinLead(event *calendar.Event) (time.Duration, bool) {
+	if event.MeetingURL == "" || event.IsDeclined() || !event.HasOtherAttendees() {
+		return 0, false
+	}
+	for _, rule := range AutoJoinRules {
+		if rule.Title != nil && !rule.Title.MatchString(event.Summary) {
+			continue
+		}
+		if rule.Calendar != "" && rule.Calendar != event.CalendarID {
+			continue
+		}
+		return rule.Lead, true
+	}
+	return 0, false
+}
+
+// autoJoinKey identifies one occurrence of an event
+func autoJoinKey(event *calendar.Event) string {
+	return event.Id + "|" + event.StartTime.String()
+}
+
+// RenderAutoJoinBanner renders the countdown to auto-joining a meeting
+func (s Styles) RenderAutoJoinBanner(event *calendar.Event, remaining time.Duration) string {
+	seconds := int(remaining.Round(time.Second).Seconds())
+	text := fmt.Sprintf("⏱ Joining %s in %ds · x to cancel", event.Summary, max(seconds, 0))
+	return s.Selected.Render(s.truncate(text, s.width-2))
+}
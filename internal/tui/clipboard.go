@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// syncedOutput is stdout with writes serialized, so that escape sequences
+// sent between renders never land in the middle of a frame. It keeps the
+// file's descriptor so bubbletea still sees a terminal.
+type syncedOutput struct {
+	*os.File
+	mu sync.Mutex
+}
+
+// Write writes b to the file while no other write is in progress
+func (o *syncedOutput) Write(b []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.File.Write(b)
+}
+
+// programOutput is where the interactive program renders, shared with the
+// clipboard and background escape sequences so their writes go between frames
+var programOutput = &syncedOutput{File: os.Stdout}
+
+// copyToClipboard sets the terminal's clipboard with an OSC 52 escape
+// sequence, which works over SSH and is passed through tmux and screen
+func copyToClipboard(text string) error {
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	_, err := programOutput.Write([]byte(seq.String()))
+	return err
+}
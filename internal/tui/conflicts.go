@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"fmt"
+
+	"oredavids.com/myCal/internal/calendar"
+)
+
+// maxHeaderConflicts is how many of today's conflicts the header lists
+const maxHeaderConflicts = 3
+
+// conflictText notes which events overlap an event, e.g. "⚠ Overlaps Design
+// Review", or "" when none do
+func conflictText(event *calendar.Event) string {
+	switch len(event.Conflicts) {
+	case 0:
+		return ""
+	case 1:
+		return "⚠ Overlaps " + event.Conflicts[0].Summary
+	default:
+		return fmt.Sprintf("⚠ Overlaps %s and %d more", event.Conflicts[0].Summary, len(event.Conflicts)-1)
+	}
+}
+
+// conflictLines describes today's conflicts for the header, e.g. "⚠ 10:30–11:00
+// AM: All Hands overlaps Design Review"
+func conflictLines(conflicts []calendar.Conflict) []string {
+	var lines []string
+	for i, c := range conflicts {
+		if i == maxHeaderConflicts && len(conflicts) == i+1 {
+			lines = append(lines, "⚠ 1 more conflict today")
+			break
+		}
+		if i == maxHeaderConflicts {
+			lines = append(lines, fmt.Sprintf("⚠ %d more conflicts today", len(conflicts)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("⚠ %s: %s overlaps %s", FormatTimeRange(c.Start, c.End), c.First.Summary, c.Second.Summary))
+	}
+	return lines
+}
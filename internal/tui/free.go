@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"oredavids.com/myCal/internal/calendar"
+)
+
+// FreeTimeOptions are used by the free time view in watch mode
+var FreeTimeOptions = calendar.FreeSlotOptions{Duration: 30 * time.Minute}
+
+// freeTimeDays is how many days ahead, starting today, the watch mode free
+// time view looks
+const freeTimeDays = 7
+
+// RenderFreeSlots renders the open slots in [from, to) grouped by day, with
+// the total free time below
+func (s Styles) RenderFreeSlots(slots []calendar.Slot, from time.Time, to time.Time) string {
+	if PlainOutput {
+		return renderPlainFreeSlots(slots, from, to)
+	}
+
+	var b strings.Builder
+	if len(slots) == 0 {
+		b.WriteString(s.NoEvents.Render("No free time in working hours"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	box := s.EventBox
+	if s.width > 0 {
+		box = box.Width(s.width - 2)
+	}
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		daySlots := slotsOn(slots, day)
+		if len(daySlots) == 0 {
+			continue
+		}
+
+		width := slotTextWidth(daySlots)
+		var rows []string
+		for _, slot := range daySlots {
+			text := FormatTimeRange(slot.Start, slot.End)
+			rows = append(rows, s.EventTime.Render(text)+strings.Repeat(" ", width-lipgloss.Width(text)+2)+
+				s.Label.Render(formatShortDuration(slot.Duration())))
+		}
+		b.WriteString(s.RenderSectionTitle(agendaDayTitle(day), "🟢"))
+		b.WriteString("\n")
+		b.WriteString(box.Render(lipgloss.JoinVertical(lipgloss.Left, rows...)))
+		b.WriteString("\n")
+	}
+	b.WriteString(s.NoEvents.Render(freeTotalText(slots)))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderPlainFreeSlots renders the open slots as plain text
+func renderPlainFreeSlots(slots []calendar.Slot, from time.Time, to time.Time) string {
+	if len(slots) == 0 {
+		return "No free time in working hours\n"
+	}
+
+	var b strings.Builder
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		daySlots := slotsOn(slots, day)
+		if len(daySlots) == 0 {
+			continue
+		}
+		b.WriteString(agendaDayTitle(day) + "\n")
+		width := slotTextWidth(daySlots)
+		for _, slot := range daySlots {
+			text := FormatTimeRange(slot.Start, slot.End)
+			fmt.Fprintf(&b, "  %s%s  %s\n", text, strings.Repeat(" ", width-lipgloss.Width(text)), formatShortDuration(slot.Duration()))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(freeTotalText(slots) + "\n")
+	return b.String()
+}
+
+// slotsOn returns the slots starting on day
+func slotsOn(slots []calendar.Slot, day time.Time) []calendar.Slot {
+	next := day.AddDate(0, 0, 1)
+	var matched []calendar.Slot
+	for _, slot := range slots {
+		if !slot.Start.Before(day) && slot.Start.Before(next) {
+			matched = append(matched, slot)
+		}
+	}
+	return matched
+}
+
+// slotTextWidth returns the width of the widest slot time range, for
+// aligning the durations beside them
+func slotTextWidth(slots []calendar.Slot) int {
+	width := 0
+	for _, slot := range slots {
+		width = max(width, lipgloss.Width(FormatTimeRange(slot.Start, slot.End)))
+	}
+	return width
+}
+
+// freeTotalText sums up the open slots, e.g. "9h 30m free in 6 slots"
+func freeTotalText(slots []calendar.Slot) string {
+	var total time.Duration
+	for _, slot := range slots {
+		total += slot.Duration()
+	}
+	text := formatHours(total)
+	if len(slots) == 1 {
+		return text + " free in 1 slot"
+	}
+	return fmt.Sprintf("%s free in %d slots", text, len(slots))
+}
+
+// formatHours formats a total in hours and minutes, e.g. "38h 30m", rather
+// than days, which would read as calendar days
+func formatHours(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d > 0 && d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	text := fmt.Sprintf("%dh", int(d.Hours()))
+	if minutes := int(d.Minutes()) % 60; minutes > 0 {
+		text += fmt.Sprintf(" %dm", minutes)
+	}
+	return text
+}
+
+// RenderFreeHelp renders the help shown with the free time view
+func (s Styles) RenderFreeHelp() string {
+	return s.Help.Render(s.truncate("↑/↓ scroll • r refresh • f/esc back • q quit", s.width))
+}
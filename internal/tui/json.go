@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"encoding/json"
+	"time"
+
+	"oredavids.com/myCal/internal/calendar"
+)
+
+// jsonEvent is an event in JSON output
+type jsonEvent struct {
+	ID              string    `json:"id,omitempty"`
+	Title           string    `json:"title"`
+	Calendar        string    `json:"calendar,omitempty"`
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	AllDay          bool      `json:"allDay"`
+	Location        string    `json:"location,omitempty"`
+	MeetingURL      string    `json:"meetingUrl,omitempty"`
+	MeetingProvider string    `json:"meetingProvider,omitempty"`
+	Link            string    `json:"link,omitempty"`
+	Conflicts       []jsonRef `json:"conflicts,omitempty"`
+}
+
+// jsonRef names another event
+type jsonRef struct {
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title"`
+}
+
+// jsonConflict is two overlapping events in JSON output
+type jsonConflict struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Events []jsonRef `json:"events"`
+}
+
+// RenderJSON renders the static output as JSON
+func RenderJSON(data RenderData) (string, error) {
+	output := struct {
+		Today     []jsonEvent    `json:"today"`
+		Upcoming  []jsonEvent    `json:"upcoming"`
+		Next      *jsonEvent     `json:"next"`
+		Conflicts []jsonConflict `json:"conflicts"`
+		Hidden    int            `json:"hidden"`
+	}{
+		Today:     jsonEvents(data.TodayEvents),
+		Upcoming:  jsonEvents(data.UpcomingEvents),
+		Conflicts: jsonConflicts(data.Conflicts),
+		Hidden:    data.Hidden,
+	}
+	if data.NextEvent != nil {
+		next := newJSONEvent(data.NextEvent)
+		output.Next = &next
+	}
+	return marshalJSON(output)
+}
+
+// RenderAgendaJSON renders the agenda as JSON
+func RenderAgendaJSON(events []*calendar.Event, hidden int) (string, error) {
+	return marshalJSON(struct {
+		Events    []jsonEvent    `json:"events"`
+		Conflicts []jsonConflict `json:"conflicts"`
+		Hidden    int            `json:"hidden"`
+	}{
+		Events:    jsonEvents(events),
+		Conflicts: jsonConflicts(calendar.FindConflicts(events)),
+		Hidden:    hidden,
+	})
+}
+
+// marshalJSON indents v as JSON
+func marshalJSON(v any) (string, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
+
+// jsonEvents converts events for JSON output, never returning nil so empty
+// lists are written as []
+func jsonEvents(events []*calendar.Event) []jsonEvent {
+	converted := make([]jsonEvent, 0, len(events))
+	for _, event := range events {
+		converted = append(converted, newJSONEvent(event))
+	}
+	return converted
+}
+
+// newJSONEvent converts an event for JSON output
+func newJSONEvent(event *calendar.Event) jsonEvent {
+	converted := jsonEvent{
+		ID:              event.Id,
+		Title:           event.Summary,
+		Calendar:        event.CalendarID,
+		Start:           event.StartTime,
+		End:             event.EndTime,
+		AllDay:          event.IsAllDay,
+		Location:        event.Location,
+		MeetingURL:      event.MeetingURL,
+		MeetingProvider: event.MeetingProvider,
+		Link:            event.HtmlLink,
+	}
+	for _, other := range event.Conflicts {
+		converted.Conflicts = append(converted.Conflicts, jsonRef{ID: other.Id, Title: other.Summary})
+	}
+	return converted
+}
+
+// jsonConflicts converts conflicts for JSON output
+func jsonConflicts(conflicts []calendar.Conflict) []jsonConflict {
+	converted := make([]jsonConflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		converted = append(converted, jsonConflict{
+			Start: c.Start,
+			End:   c.End,
+			Events: []jsonRef{
+				{ID: c.First.Id, Title: c.First.Summary},
+				{ID: c.Second.Id, Title: c.Second.Summary},
+			},
+		})
+	}
+	return converted
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pkg/browser"
+
+	"oredavids.com/myCal/internal/calendar"
+	"oredavids.com/myCal/internal/config"
+)
+
+// launchMsg reports the outcome of joining a meeting
+type launchMsg struct {
+	event *calendar.Event
+	err   error
+}
+
+// launchWait is how long a launcher command is given to fail before it is
+// left running in the background, e.g. as a meeting client in the foreground
+const launchWait = 2 * time.Second
+
+// quotedPlaceholder matches a placeholder the launcher quoted itself, which
+// doubles the quotes myCal adds
+var quotedPlaceholder = regexp.MustCompile(`['"]\{(url|native|id)\}['"]`)
+
+// launcherFor returns the configured launcher for the event's provider,
+// matched exactly and then case-insensitively, falling back to the "default"
+// entry and then the browser
+func launcherFor(event *calendar.Event) string {
+	launchers := config.Get().Launchers
+	if event.MeetingProvider != "" {
+		if launcher, ok := launchers[event.MeetingProvider]; ok {
+			return launcher
+		}
+		// Sort the names so that keys differing only in case always resolve
+		// the same way
+		for _, provider := range slices.Sorted(maps.Keys(launchers)) {
+			if strings.EqualFold(provider, event.MeetingProvider) {
+				return launchers[provider]
+			}
+		}
+	}
+	if launcher, ok := launchers["default"]; ok {
+		return launcher
+	}
+	return config.LaunchBrowser
+}
+
+// launchMeeting returns a command that opens the event's meeting with its
+// launcher and reports the result
+func launchMeeting(event *calendar.Event) tea.Cmd {
+	return func() tea.Msg {
+		return launchMsg{event, openMeeting(event)}
+	}
+}
+
+// openMeeting opens the event's meeting with its launcher
+func openMeeting(event *calendar.Event) error {
+	switch launcher := launcherFor(event); launcher {
+	case config.LaunchBrowser, "":
+		return browser.OpenURL(event.MeetingURL)
+	case config.LaunchNative:
+		return browser.OpenURL(event.NativeMeetingURL())
+	default:
+		command := strings.NewReplacer(
+			"{url}", shellQuote(event.MeetingURL),
+			"{native}", shellQuote(event.NativeMeetingURL()),
+			"{id}", shellQuote(event.MeetingID()),
+		).Replace(launcher)
+
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/C", command)
+		} else {
+			cmd = exec.Command("sh", "-c", command)
+		}
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- cmd.Wait() }()
+		select {
+		case err := <-done:
+			if err == nil {
+				return nil
+			}
+			// The first line of output usually says what went wrong
+			if msg, _, _ := strings.Cut(strings.TrimSpace(out.String()), "\n"); msg != "" {
+				err = fmt.Errorf("%v: %s", err, msg)
+			}
+			if quotedPlaceholder.MatchString(launcher) {
+				err = fmt.Errorf("%v (placeholders are quoted for you; remove the quotes around them)", err)
+			}
+			return err
+		case <-time.After(launchWait):
+			// Still running, so it started; it's reaped when it exits
+			return nil
+		}
+	}
+}
+
+// shellQuote quotes s as a single shell word
+func shellQuote(s string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
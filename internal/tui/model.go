@@ -7,23 +7,67 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/pkg/browser"
 	gcal "google.golang.org/api/calendar/v3"
 
 	"oredavids.com/myCal/internal/calendar"
+	"oredavids.com/myCal/internal/config"
 )
 
 // Model is the bubbletea model for the TUI
 type Model struct {
 	calendarService *gcal.Service
+	styles          Styles
+	themeName       string
 	todayEvents     []*calendar.Event
 	upcomingEvents  []*calendar.Event
+	fetchedToday    []*calendar.Event // including events hidden by filters
+	fetchedUpcoming []*calendar.Event
+	showHidden      bool
+	hidden          int // events the filters hid from the lists
 	nextEvent       *calendar.Event
 	selectedIndex   int
 	allEvents       []*calendar.Event // combined list for selection
 	status          string
+	statusIsError   bool
 	lastRefresh     time.Time
 	err             error
+
+	// Terminal size and the first line of the event lists shown in the viewport
+	width  int
+	height int
+	offset int
+
+	// Search state: searching is set while the query is typed, showingResults
+	// while the results replace the event lists. searchPool holds every event
+	// in the search range, which the query filters locally as it is typed.
+	searching      bool
+	showingResults bool
+	searchQuery    string
+	searchPool     []*calendar.Event
+
+	// Free time view state: the open slots in [freeFrom, freeTo) replace
+	// the event lists while showingFree is set
+	showingFree bool
+	freeSlots   []calendar.Slot
+	freeFrom    time.Time
+	freeTo      time.Time
+
+	// Auto-join state: the meeting whose banner is showing, when it will be
+	// joined, and the meetings already joined or cancelled
+	autoJoinEvent *calendar.Event
+	autoJoinAt    time.Time
+	autoJoinDone  map[string]bool
+
+	// Last left click, for detecting double clicks
+	lastClick      time.Time
+	lastClickIndex int
+
+	// Theme picker state
+	pickingTheme bool
+	themeNames   []string
+	themeIndex   int
 }
 
 // tickMsg is sent every second to update the countdown
@@ -32,12 +76,15 @@ type tickMsg time.Time
 // refreshMsg is sent when data needs to be refreshed
 type refreshMsg struct{}
 
-// NewModel creates a new TUI model
-func NewModel(srv *gcal.Service) Model {
+// NewModel creates a new TUI model using the theme with the given key in Themes
+func NewModel(srv *gcal.Service, themeName string) Model {
 	return Model{
 		calendarService: srv,
+		styles:          NewStyles(Themes[themeName]).WithClickableLinks(),
+		themeName:       themeName,
 		selectedIndex:   0,
 		lastRefresh:     time.Now(),
+		autoJoinDone:    make(map[string]bool),
 	}
 }
 
@@ -53,53 +100,138 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.pickingTheme {
+			return m.updateThemePicker(msg)
+		}
+		if m.searching {
+			return m.updateSearchPrompt(msg)
+		}
+		if m.showingFree {
+			return m.updateFreeView(msg)
+		}
+
 		switch msg.String() {
-		case "q", "ctrl+c", "esc":
+		case "x":
+			m.cancelAutoJoin()
+
+		case "esc":
+			if m.autoJoinEvent != nil {
+				m.cancelAutoJoin()
+				return m, nil
+			}
+			if m.showingResults {
+				m.clearSearch()
+				return m, nil
+			}
+			return m, tea.Quit
+
+		case "q", "ctrl+c":
 			return m, tea.Quit
 
 		case "up", "k":
 			if m.selectedIndex > 0 {
 				m.selectedIndex--
 			}
+			m.keepSelectionVisible()
 
 		case "down", "j":
 			if m.selectedIndex < len(m.allEvents)-1 {
 				m.selectedIndex++
 			}
+			m.keepSelectionVisible()
 
 		case "enter":
-			if m.selectedIndex < len(m.allEvents) {
-				event := m.allEvents[m.selectedIndex]
-				if event.MeetingURL != "" {
-					browser.OpenURL(event.MeetingURL)
-					m.status = fmt.Sprintf("Opening %s...", event.Summary)
-				} else {
-					m.status = "No meeting link for this event"
-				}
-			}
+			return m, m.joinSelected()
 
 		case "r":
-			m.status = "Refreshing..."
+			m.setStatus("Refreshing...")
+			if m.showingResults {
+				return m, tea.Batch(m.fetchEvents(), m.fetchSearchPool())
+			}
 			return m, m.fetchEvents()
+
+		case "t":
+			m.openThemePicker()
+			m.keepSelectionVisible()
+
+		case "c", "i", "p":
+			m.copySelected(msg.String())
+
+		case "/":
+			return m, m.openSearch()
+
+		case "f":
+			return m, m.openFreeView()
+
+		case "h":
+			m.showHidden = !m.showHidden
+			m.applyFilter()
+			if m.showingResults {
+				m.applySearch()
+			}
+			m.keepSelectionVisible()
+
+		case "n", "N":
+			if m.showingResults && len(m.allEvents) > 0 {
+				step := 1
+				if msg.String() == "N" {
+					step = len(m.allEvents) - 1
+				}
+				m.selectedIndex = (m.selectedIndex + step) % len(m.allEvents)
+				m.keepSelectionVisible()
+			}
+		}
+
+	case tea.MouseMsg:
+		if !m.pickingTheme {
+			return m, m.handleMouse(msg)
+		}
+
+	case launchMsg:
+		if msg.err != nil {
+			m.setError(fmt.Sprintf("Couldn't open %s: %v", msg.event.Summary, msg.err))
 		}
 
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.keepSelectionVisible()
+
 	case tickMsg:
+		cmds := []tea.Cmd{tickEvery(), m.checkAutoJoin()}
 		// Check if we should auto-refresh (every 5 minutes)
 		if time.Since(m.lastRefresh) > 5*time.Minute {
-			return m, tea.Batch(tickEvery(), m.fetchEvents())
+			cmds = append(cmds, m.fetchEvents())
 		}
-		return m, tickEvery()
+		return m, tea.Batch(cmds...)
 
 	case eventsMsg:
-		m.todayEvents = msg.today
-		m.upcomingEvents = msg.upcoming
+		m.fetchedToday = msg.today
+		m.fetchedUpcoming = msg.upcoming
 		m.nextEvent = msg.next
-		m.allEvents = append(m.todayEvents, m.upcomingEvents...)
 		m.lastRefresh = time.Now()
-		m.status = ""
+		m.setStatus("")
+		m.applyFilter()
+		if m.showingResults {
+			return m, nil
+		}
 		if m.selectedIndex >= len(m.allEvents) && len(m.allEvents) > 0 {
 			m.selectedIndex = len(m.allEvents) - 1
 		}
+		m.keepSelectionVisible()
+
+	case freeSlotsMsg:
+		m.freeSlots = msg.slots
+		m.freeFrom = msg.from
+		m.freeTo = msg.to
+		m.setStatus("")
+
+	case searchPoolMsg:
+		m.searchPool = msg.events
+		m.setStatus("")
+		if m.showingResults {
+			m.applySearch()
+		}
 
 	case errMsg:
 		m.err = msg.err
@@ -108,60 +240,557 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// applyFilter sets the event lists from the fetched events, leaving out the
+// hidden ones unless they are being shown
+func (m *Model) applyFilter() {
+	var hiddenToday, hiddenUpcoming int
+	m.todayEvents, hiddenToday = calendar.Visible(m.fetchedToday)
+	m.upcomingEvents, hiddenUpcoming = calendar.Visible(m.fetchedUpcoming)
+	m.hidden = hiddenToday + hiddenUpcoming
+	if m.showHidden {
+		m.todayEvents = m.fetchedToday
+		m.upcomingEvents = m.fetchedUpcoming
+	}
+	if !m.showingResults {
+		m.allEvents = append(m.todayEvents, m.upcomingEvents...)
+	}
+}
+
+// joinSelected returns a command that opens the selected event's meeting
+// with its configured launcher
+func (m *Model) joinSelected() tea.Cmd {
+	if m.selectedIndex >= len(m.allEvents) {
+		return nil
+	}
+	event := m.allEvents[m.selectedIndex]
+	if event.MeetingURL == "" {
+		m.setStatus("No meeting link for this event")
+		return nil
+	}
+	m.setStatus(fmt.Sprintf("Opening %s...", event.Summary))
+	return launchMeeting(event)
+}
+
+// copySelected copies the selected event's meeting link ("c"), meeting ID
+// ("i") or passcode ("p") to the clipboard
+func (m *Model) copySelected(key string) {
+	if m.selectedIndex >= len(m.allEvents) {
+		return
+	}
+	event := m.allEvents[m.selectedIndex]
+
+	var what, text string
+	switch key {
+	case "c":
+		what, text = "meeting link", event.MeetingURL
+	case "i":
+		what, text = "meeting ID", event.MeetingID()
+	case "p":
+		what, text = "passcode", event.Passcode
+	}
+
+	if text == "" {
+		m.setStatus(fmt.Sprintf("No %s for this event", what))
+		return
+	}
+	if err := copyToClipboard(text); err != nil {
+		m.setError(fmt.Sprintf("Couldn't copy the %s: %v", what, err))
+		return
+	}
+	m.setStatus(fmt.Sprintf("Copied the %s of %s", what, event.Summary))
+}
+
+// checkAutoJoin shows the banner once the next meeting is about to be
+// auto-joined, and returns a command joining it when the countdown ends
+func (m *Model) checkAutoJoin() tea.Cmd {
+	event := m.nextEvent
+	if event == nil || m.autoJoinDone[autoJoinKey(event)] {
+		m.autoJoinEvent = nil
+		return nil
+	}
+	lead, ok := autoJoinLead(event)
+	if !ok {
+		m.autoJoinEvent = nil
+		return nil
+	}
+
+	// Always give a full warning, even when watch mode starts late
+	if m.autoJoinEvent == nil || autoJoinKey(m.autoJoinEvent) != autoJoinKey(event) {
+		joinAt := event.StartTime.Add(-lead)
+		if time.Until(joinAt) > autoJoinWarning || event.InProgress() {
+			return nil
+		}
+		m.autoJoinEvent = event
+		m.autoJoinAt = latest(joinAt, time.Now().Add(autoJoinWarning))
+	}
+
+	if time.Now().Before(m.autoJoinAt) {
+		return nil
+	}
+	m.autoJoinDone[autoJoinKey(event)] = true
+	m.autoJoinEvent = nil
+	m.setStatus(fmt.Sprintf("Auto-joining %s...", event.Summary))
+	return launchMeeting(event)
+}
+
+// cancelAutoJoin stops the pending auto-join
+func (m *Model) cancelAutoJoin() {
+	if m.autoJoinEvent == nil {
+		return
+	}
+	m.autoJoinDone[autoJoinKey(m.autoJoinEvent)] = true
+	m.setStatus(fmt.Sprintf("Won't auto-join %s", m.autoJoinEvent.Summary))
+	m.autoJoinEvent = nil
+}
+
+// latest returns the later of two times
+func latest(a time.Time, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// setStatus shows a message in the status line
+func (m *Model) setStatus(status string) {
+	m.status = status
+	m.statusIsError = false
+}
+
+// setError shows a failure in the status line
+func (m *Model) setError(status string) {
+	m.status = status
+	m.statusIsError = true
+}
+
+// doubleClickTime is the longest gap between two clicks of a double click
+const doubleClickTime = 500 * time.Millisecond
+
+// handleMouse scrolls the event lists with the wheel, selects the clicked
+// event, joins it on a double click and opens clicked meeting/[Cal] labels
+func (m *Model) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.scroll(-3)
+		return nil
+	case tea.MouseButtonWheelDown:
+		m.scroll(3)
+		return nil
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	styles := m.sizedStyles()
+	top := m.renderTop(styles)
+	row := msg.Y - lipgloss.Height(top)
+	if row < 0 || (m.height > 0 && row >= m.bodyHeight(top, m.renderBottom(styles))) {
+		return nil
+	}
+	line := row + m.offset
+	_, spans := m.renderBody(styles)
+
+	for i, span := range spans {
+		if line < span.first || line > span.last {
+			continue
+		}
+
+		event := m.allEvents[i]
+		isToday := i < len(m.todayEvents) && !m.showingResults
+		if line == span.first+1 {
+			switch url := styles.linkAt(event, isToday, msg.X); url {
+			case "":
+			case event.MeetingURL:
+				m.selectedIndex = i
+				return m.joinSelected()
+			default:
+				m.selectedIndex = i
+				if err := browser.OpenURL(url); err != nil {
+					m.setError(fmt.Sprintf("Couldn't open %s: %v", event.Summary, err))
+				} else {
+					m.setStatus(fmt.Sprintf("Opening %s in Google Calendar...", event.Summary))
+				}
+				return nil
+			}
+		}
+
+		now := time.Now()
+		if i == m.lastClickIndex && now.Sub(m.lastClick) < doubleClickTime {
+			m.selectedIndex = i
+			m.lastClick = time.Time{}
+			return m.joinSelected()
+		}
+		m.selectedIndex = i
+		m.lastClick = now
+		m.lastClickIndex = i
+		return nil
+	}
+	return nil
+}
+
+// scroll moves the viewport by delta lines without changing the selection
+func (m *Model) scroll(delta int) {
+	styles := m.sizedStyles()
+	body, _ := m.renderBody(styles)
+	visible := m.bodyHeight(m.renderTop(styles), m.renderBottom(styles))
+	if visible == 0 {
+		return
+	}
+	m.offset = max(min(m.offset+delta, lipgloss.Height(body)-visible), 0)
+}
+
+// openSearch shows the search prompt and, the first time, returns a command
+// fetching the events of the search range
+func (m *Model) openSearch() tea.Cmd {
+	m.searching = true
+	if m.showingResults {
+		return nil
+	}
+
+	m.showingResults = true
+	m.searchQuery = ""
+	m.applySearch()
+	if m.searchPool != nil {
+		return nil
+	}
+	m.setStatus(fmt.Sprintf("Loading events for the next %d days...", config.GetSearchDays()))
+	return m.fetchSearchPool()
+}
+
+// updateSearchPrompt handles keys while the search query is typed, filtering
+// the results as it changes
+func (m Model) updateSearchPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.clearSearch()
+
+	case tea.KeyEnter:
+		m.searching = false
+		m.keepSelectionVisible()
+
+	case tea.KeyBackspace:
+		if query := []rune(m.searchQuery); len(query) > 0 {
+			m.searchQuery = string(query[:len(query)-1])
+			m.applySearch()
+		}
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.searchQuery += string(msg.Runes)
+		m.applySearch()
+	}
+	return m, nil
+}
+
+// applySearch shows the events of the search pool matching the query
+func (m *Model) applySearch() {
+	pool := m.searchPool
+	if !m.showHidden {
+		pool, _ = calendar.Visible(pool)
+	}
+	m.allEvents = calendar.FilterEvents(pool, m.searchQuery)
+	m.selectedIndex = 0
+	m.offset = 0
+	m.keepSelectionVisible()
+}
+
+// clearSearch closes the search and shows the event lists again. The search
+// pool is dropped so the next search sees fresh events.
+func (m *Model) clearSearch() {
+	m.searching = false
+	m.showingResults = false
+	m.searchQuery = ""
+	m.searchPool = nil
+	m.applyFilter()
+	m.selectedIndex = 0
+	m.offset = 0
+	m.keepSelectionVisible()
+}
+
+// openFreeView replaces the event lists with the open slots of the coming
+// days and returns a command finding them
+func (m *Model) openFreeView() tea.Cmd {
+	if m.showingResults {
+		m.clearSearch()
+	}
+	m.showingFree = true
+	m.freeSlots = nil
+	m.offset = 0
+	m.setStatus("Finding free time...")
+	return m.fetchFreeSlots()
+}
+
+// updateFreeView handles keys while the free time view is shown
+func (m Model) updateFreeView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "f":
+		m.showingFree = false
+		m.offset = 0
+		m.keepSelectionVisible()
+
+	case "up", "k":
+		m.scroll(-1)
+
+	case "down", "j":
+		m.scroll(1)
+
+	case "r":
+		m.setStatus("Refreshing...")
+		return m, tea.Batch(m.fetchEvents(), m.fetchFreeSlots())
+
+	case "x":
+		m.cancelAutoJoin()
+	}
+	return m, nil
+}
+
+// openThemePicker shows the theme list with the active theme selected
+func (m *Model) openThemePicker() {
+	m.pickingTheme = true
+	m.themeNames = GetThemeNames()
+	m.themeIndex = 0
+	for i, name := range m.themeNames {
+		if name == m.themeName {
+			m.themeIndex = i
+		}
+	}
+}
+
+// updateThemePicker handles keys while the theme picker is open. Moving the
+// selection restyles the agenda immediately so it previews the theme; the
+// model's own theme only changes once the choice is saved.
+func (m Model) updateThemePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q", "t":
+		m.pickingTheme = false
+		m.styles = NewStyles(Themes[m.themeName]).WithClickableLinks()
+		syncThemeBackground(m.styles.Theme)
+		m.keepSelectionVisible()
+
+	case "up", "k":
+		if m.themeIndex > 0 {
+			m.themeIndex--
+		}
+		m.styles = NewStyles(Themes[m.themeNames[m.themeIndex]]).WithClickableLinks()
+		syncThemeBackground(m.styles.Theme)
+
+	case "down", "j":
+		if m.themeIndex < len(m.themeNames)-1 {
+			m.themeIndex++
+		}
+		m.styles = NewStyles(Themes[m.themeNames[m.themeIndex]]).WithClickableLinks()
+		syncThemeBackground(m.styles.Theme)
+
+	case "enter":
+		m.pickingTheme = false
+		m.themeName = m.themeNames[m.themeIndex]
+		m.keepSelectionVisible()
+		cfg := config.Get()
+		cfg.Theme = m.themeName
+		if err := config.Save(cfg); err != nil {
+			m.setError(fmt.Sprintf("Theme set to %s, but not saved: %v", m.styles.Theme.Name, err))
+		} else {
+			m.setStatus(fmt.Sprintf("Theme set to %s", m.styles.Theme.Name))
+		}
+	}
+	return m, nil
+}
+
 // View renders the UI
 func (m Model) View() string {
+	styles := m.sizedStyles()
+	top := m.renderTop(styles)
+	body, _ := m.renderBody(styles)
+	bottom := m.renderBottom(styles)
+
+	if visible := m.bodyHeight(top, bottom); visible > 0 {
+		lines := strings.Split(body, "\n")
+		if len(lines) > visible {
+			offset := min(m.offset, len(lines)-visible)
+			body = strings.Join(lines[offset:offset+visible], "\n")
+		}
+	}
+
+	view := lipgloss.JoinVertical(lipgloss.Left, top, body, bottom)
+	if m.pickingTheme {
+		view = lipgloss.JoinHorizontal(lipgloss.Top, view, "  ", m.styles.RenderThemePicker(m.themeNames, m.themeIndex))
+	}
+	return view
+}
+
+// sizedStyles returns the styles fitted to the terminal width, leaving room
+// for the theme picker while it is open
+func (m Model) sizedStyles() Styles {
+	width := m.width
+	if width > 0 && m.pickingTheme {
+		width -= lipgloss.Width(m.styles.RenderThemePicker(m.themeNames, m.themeIndex)) + 2
+	}
+	return m.styles.WithWidth(width)
+}
+
+// bodyHeight returns how many lines of event lists fit between the top and
+// bottom of the screen, or 0 when the terminal height is unknown
+func (m Model) bodyHeight(top string, bottom string) int {
+	if m.height <= 0 {
+		return 0
+	}
+	return max(m.height-lipgloss.Height(top)-lipgloss.Height(bottom), 1)
+}
+
+// keepSelectionVisible scrolls the viewport so the selected event is shown
+func (m *Model) keepSelectionVisible() {
+	styles := m.sizedStyles()
+	body, spans := m.renderBody(styles)
+	visible := m.bodyHeight(m.renderTop(styles), m.renderBottom(styles))
+	if visible == 0 {
+		m.offset = 0
+		return
+	}
+
+	if m.selectedIndex < len(spans) {
+		span := spans[m.selectedIndex]
+		if span.last-span.first+1 > visible || span.first < m.offset {
+			m.offset = span.first
+		} else if span.last >= m.offset+visible {
+			m.offset = span.last - visible + 1
+		}
+	}
+	m.offset = max(min(m.offset, lipgloss.Height(body)-visible), 0)
+}
+
+// renderTop renders the header, countdown and status above the event lists
+func (m Model) renderTop(styles Styles) string {
 	var b strings.Builder
 
 	// Header
-	b.WriteString(renderHeader(getUserName()))
-	b.WriteString("\n")
+	b.WriteString(styles.renderHeader(getUserName(), calendar.FindConflicts(m.todayEvents)))
 
 	// Next meeting countdown
 	if m.nextEvent != nil {
-		countdown := RenderCountdown(m.nextEvent)
+		countdown := styles.RenderCountdown(m.nextEvent)
 		if countdown != "" {
-			b.WriteString(countdown)
 			b.WriteString("\n")
+			b.WriteString(styles.truncate(countdown, styles.width))
 		}
 	}
 
+	// Auto-join countdown
+	if m.autoJoinEvent != nil {
+		b.WriteString("\n")
+		b.WriteString(styles.RenderAutoJoinBanner(m.autoJoinEvent, time.Until(m.autoJoinAt)))
+	}
+
 	// Status message
 	if m.status != "" {
-		b.WriteString(StatusStyle.Render(m.status))
+		style := styles.Status
+		if m.statusIsError {
+			style = styles.Error
+		}
+		b.WriteString("\n")
+		b.WriteString(style.Render(styles.truncate(m.status, styles.width)))
+	}
+
+	return b.String()
+}
+
+// renderBody renders the event lists along with the lines each event in
+// allEvents occupies within them
+func (m Model) renderBody(styles Styles) (string, []lineSpan) {
+	var b strings.Builder
+	var spans []lineSpan
+
+	// Free time replaces the event lists
+	if m.showingFree {
+		b.WriteString("\n")
+		b.WriteString(styles.RenderSectionTitle(fmt.Sprintf("Free time (next %d days)", freeTimeDays), "🕒"))
 		b.WriteString("\n")
+		if m.freeSlots != nil {
+			b.WriteString(styles.RenderFreeSlots(m.freeSlots, m.freeFrom, m.freeTo))
+		}
+		return strings.TrimSuffix(b.String(), "\n"), nil
+	}
+
+	// Search results replace the event lists
+	if m.showingResults {
+		b.WriteString("\n")
+		b.WriteString(styles.RenderSectionTitle(fmt.Sprintf("Search (next %d days)", config.GetSearchDays()), "🔍"))
+		b.WriteString("\n")
+		if len(m.allEvents) == 0 {
+			b.WriteString(styles.NoEvents.Render("No matching events"))
+			return b.String(), nil
+		}
+		styles = styles.WithHighlight(m.searchQuery)
+		spans = styles.eventLines(m.allEvents, false, m.selectedIndex, strings.Count(b.String(), "\n"))
+		b.WriteString(styles.RenderEventList(m.allEvents, false, m.selectedIndex))
+		return b.String(), spans
 	}
 
 	// Today's events
 	b.WriteString("\n")
-	b.WriteString(RenderSectionTitle("Today", "🗓"))
+	b.WriteString(styles.RenderSectionTitle("Today", "🗓"))
 	b.WriteString("\n")
 	if len(m.todayEvents) == 0 {
-		b.WriteString(NoEventsStyle.Render("No events remaining today"))
+		b.WriteString(styles.NoEvents.Render("No events remaining today"))
 	} else {
-		b.WriteString(RenderEventList(m.todayEvents, true, m.selectedIndex))
+		spans = append(spans, styles.eventLines(m.todayEvents, true, m.selectedIndex, strings.Count(b.String(), "\n"))...)
+		b.WriteString(styles.RenderEventList(m.todayEvents, true, m.selectedIndex))
 	}
-	b.WriteString("\n")
 
 	// Upcoming events (only show if today has < 3 events)
 	if len(m.todayEvents) < 3 && len(m.upcomingEvents) > 0 {
-		b.WriteString("\n")
-		b.WriteString(RenderSectionTitle("Upcoming", "🗓"))
+		b.WriteString("\n\n")
+		b.WriteString(styles.RenderSectionTitle("Upcoming", "🗓"))
 		b.WriteString("\n")
 		// Adjust selected index for upcoming section
 		upcomingSelectedIndex := m.selectedIndex - len(m.todayEvents)
-		b.WriteString(RenderEventList(m.upcomingEvents, false, upcomingSelectedIndex))
+		spans = append(spans, styles.eventLines(m.upcomingEvents, false, upcomingSelectedIndex, strings.Count(b.String(), "\n"))...)
+		b.WriteString(styles.RenderEventList(m.upcomingEvents, false, upcomingSelectedIndex))
+	}
+
+	// Events hidden by filters
+	if m.hidden > 0 {
 		b.WriteString("\n")
+		if m.showHidden {
+			b.WriteString(styles.NoEvents.Render("Showing " + hiddenCountText(m.hidden) + " (h to hide)"))
+		} else {
+			b.WriteString(styles.NoEvents.Render(hiddenCountText(m.hidden) + " (h to show)"))
+		}
 	}
 
-	// Help
-	b.WriteString(RenderHelp())
-	b.WriteString("\n")
+	return b.String(), spans
+}
+
+// renderBottom renders the help and any error below the event lists
+func (m Model) renderBottom(styles Styles) string {
+	var b strings.Builder
+
+	// Search prompt or help
+	switch {
+	case m.searching:
+		b.WriteString(styles.RenderSearchPrompt(m.searchQuery))
+	case m.showingResults:
+		b.WriteString(styles.RenderSearchHelp())
+	case m.showingFree:
+		b.WriteString(styles.RenderFreeHelp())
+	default:
+		b.WriteString(styles.RenderHelp())
+	}
 
 	// Error display
 	if m.err != nil {
-		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444"))
-		b.WriteString(errStyle.Render(fmt.Sprintf("Error: %v", m.err)))
 		b.WriteString("\n")
+		b.WriteString(styles.Error.Render(styles.truncate(fmt.Sprintf("Error: %v", m.err), styles.width)))
 	}
 
 	return b.String()
@@ -174,6 +803,18 @@ type eventsMsg struct {
 	next     *calendar.Event
 }
 
+// searchPoolMsg carries the events of the search range
+type searchPoolMsg struct {
+	events []*calendar.Event
+}
+
+// freeSlotsMsg carries the open slots in [from, to)
+type freeSlotsMsg struct {
+	slots []calendar.Slot
+	from  time.Time
+	to    time.Time
+}
+
 // errMsg carries an error
 type errMsg struct {
 	err error
@@ -188,7 +829,7 @@ func (m Model) fetchEvents() tea.Cmd {
 		}
 
 		var upcoming []*calendar.Event
-		if len(today) < 3 {
+		if visible, _ := calendar.Visible(today); len(visible) < 3 {
 			upcoming, err = calendar.FetchUpcomingEvents(m.calendarService, 5, true)
 			if err != nil {
 				return errMsg{err}
@@ -205,6 +846,41 @@ func (m Model) fetchEvents() tea.Cmd {
 	}
 }
 
+// fetchSearchPool returns a command that fetches every event in the search
+// range, starting today
+func (m Model) fetchSearchPool() tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		events, err := calendar.FetchEventsBetween(m.calendarService, from, from.AddDate(0, 0, config.GetSearchDays()))
+		if err != nil {
+			return errMsg{err}
+		}
+		if events == nil {
+			events = []*calendar.Event{}
+		}
+		return searchPoolMsg{events}
+	}
+}
+
+// fetchFreeSlots returns a command that finds the open slots from now until
+// the end of the free time range
+func (m Model) fetchFreeSlots() tea.Cmd {
+	return func() tea.Msg {
+		from := time.Now()
+		to := startOfToday().AddDate(0, 0, freeTimeDays)
+		busy, err := calendar.FetchOwnBusy(m.calendarService, from, to)
+		if err != nil {
+			return errMsg{err}
+		}
+		slots := calendar.FindFreeSlots(busy, from, to, FreeTimeOptions)
+		if slots == nil {
+			slots = []calendar.Slot{}
+		}
+		return freeSlotsMsg{slots, from, to}
+	}
+}
+
 // tickEvery returns a command that sends a tick every second
 func tickEvery() tea.Cmd {
 	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
@@ -212,9 +888,41 @@ func tickEvery() tea.Cmd {
 	})
 }
 
-// Run starts the TUI
-func Run(srv *gcal.Service) error {
-	p := tea.NewProgram(NewModel(srv), tea.WithAltScreen())
+// Run starts the TUI using the theme with the given key in Themes
+func Run(srv *gcal.Service, themeName string) error {
+	syncThemeBackground(Themes[themeName])
+	defer restoreBackground()
+
+	p := tea.NewProgram(NewModel(srv, themeName), tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithOutput(programOutput))
 	_, err := p.Run()
 	return err
 }
+
+// paintedBackground is set while the terminal shows a theme's background
+var paintedBackground bool
+
+// syncThemeBackground paints the terminal with the theme's background when
+// the theme was made for the opposite kind of terminal (e.g. a light theme in
+// a dark terminal), where its text colors would otherwise be unreadable, and
+// restores the terminal's own background otherwise. Without colors the text
+// uses the terminal's own colors, so the background is left alone.
+func syncThemeBackground(theme Theme) {
+	if PlainOutput || lipgloss.ColorProfile() == termenv.Ascii ||
+		theme.Background.TrueColor == "" || theme.IsLight() != lipgloss.HasDarkBackground() {
+		restoreBackground()
+		return
+	}
+
+	termenv.NewOutput(programOutput).SetBackgroundColor(termenv.TrueColor.Color(theme.Background.TrueColor))
+	paintedBackground = true
+}
+
+// restoreBackground undoes syncThemeBackground
+func restoreBackground() {
+	if !paintedBackground {
+		return
+	}
+	// OSC 111 resets the background to the terminal's configured default
+	fmt.Fprint(programOutput, termenv.OSC+"111"+termenv.ST)
+	paintedBackground = false
+}
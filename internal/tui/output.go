@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/term"
+
+	"oredavids.com/myCal/internal/calendar"
+)
+
+// Color modes accepted by --color
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// PlainOutput is set when static output goes somewhere other than a terminal
+// (a file, a pipe, a cron email) and should be plain text without borders,
+// hyperlinks or escape codes
+var PlainOutput bool
+
+// SetColorMode configures color output for stdout. In auto mode colors are
+// only used on a terminal and when NO_COLOR is unset; always forces styled
+// output even when piped.
+func SetColorMode(mode string) error {
+	isTTY := term.IsTerminal(int(os.Stdout.Fd()))
+
+	switch mode {
+	case ColorAuto, "":
+		PlainOutput = !isTTY
+		if !isTTY || os.Getenv("NO_COLOR") != "" {
+			lipgloss.SetColorProfile(termenv.Ascii)
+		}
+	case ColorAlways:
+		PlainOutput = false
+		profile := termenv.NewOutput(os.Stdout, termenv.WithTTY(true)).ColorProfile()
+		if profile == termenv.Ascii {
+			profile = termenv.ANSI
+		}
+		lipgloss.SetColorProfile(profile)
+	case ColorNever:
+		PlainOutput = !isTTY
+		lipgloss.SetColorProfile(termenv.Ascii)
+	default:
+		return fmt.Errorf("invalid color mode %q (want %s, %s or %s)", mode, ColorAuto, ColorAlways, ColorNever)
+	}
+
+	if PlainOutput {
+		HyperlinkSupport = false
+	}
+	return nil
+}
+
+// renderPlainStatic renders the static output as plain text
+func renderPlainStatic(data RenderData) string {
+	var b strings.Builder
+
+	now := time.Now()
+	b.WriteString(now.Format("Monday, January 2, 2006 · 3:04 PM") + "\n")
+	if data.UserName != "" {
+		fmt.Fprintf(&b, "%s, %s!\n", getGreeting(), data.UserName)
+	} else {
+		fmt.Fprintf(&b, "%s!\n", getGreeting())
+	}
+	if len(WorldClock) > 0 {
+		b.WriteString(worldClockText() + "\n")
+	}
+	for _, line := range conflictLines(data.Conflicts) {
+		b.WriteString(line + "\n")
+	}
+
+	if data.NextEvent != nil && data.NextEvent.TimeUntilStart() >= 0 {
+		fmt.Fprintf(&b, "Next: %s %s\n", data.NextEvent.Summary, FormatDuration(data.NextEvent.TimeUntilStart()))
+	}
+
+	b.WriteString("\nToday\n")
+	if len(data.TodayEvents) == 0 {
+		b.WriteString("  No events remaining today\n")
+	} else {
+		b.WriteString(plainEventList(data.TodayEvents, true, startOfToday()))
+	}
+
+	if len(data.UpcomingEvents) > 0 {
+		b.WriteString("\nUpcoming\n")
+		b.WriteString(plainEventList(data.UpcomingEvents, false, time.Time{}))
+	}
+
+	if data.Hidden > 0 {
+		b.WriteString("\n" + hiddenCountText(data.Hidden) + "\n")
+	}
+
+	return b.String()
+}
+
+// renderPlainNext renders the next event as plain text
+func renderPlainNext(event *calendar.Event) string {
+	if event == nil {
+		return "No upcoming meetings"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Next: %s %s\n", event.Summary, FormatDuration(event.TimeUntilStart()))
+	b.WriteString(plainEventList([]*calendar.Event{event}, false, time.Time{}))
+	for _, line := range meetingDetailLines(event) {
+		b.WriteString("    " + line + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderPlainAgenda renders the agenda as plain text
+func renderPlainAgenda(events []*calendar.Event, from time.Time, days int) string {
+	var b strings.Builder
+
+	for i := 0; i < days; i++ {
+		dayStart := from.AddDate(0, 0, i)
+		dayEvents := eventsBetween(events, dayStart, dayStart.AddDate(0, 0, 1))
+		if len(dayEvents) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(agendaDayTitle(dayStart) + "\n")
+		b.WriteString(plainEventList(dayEvents, true, dayStart))
+	}
+
+	if b.Len() == 0 {
+		fmt.Fprintf(&b, "No events in the next %d days\n", days)
+	}
+	return b.String()
+}
+
+// plainEventList renders one indented line per event, with its meeting link
+// below; day is as for eventTimeText
+func plainEventList(events []*calendar.Event, isToday bool, day time.Time) string {
+	var b strings.Builder
+	for _, event := range events {
+		fmt.Fprintf(&b, "  %s  %s", eventTimeText(event, isToday, day), event.Summary)
+		if !event.IsAllDay && event.InProgress() {
+			fmt.Fprintf(&b, "  (now, %s left)", formatShortDuration(time.Until(event.EndTime)))
+		}
+		b.WriteString("\n")
+		if event.MeetingURL != "" && event.MeetingProvider != "" {
+			fmt.Fprintf(&b, "    %s: %s\n", event.MeetingProvider, event.MeetingURL)
+		} else if event.MeetingURL != "" {
+			fmt.Fprintf(&b, "    %s\n", event.MeetingURL)
+		}
+		if text := conflictText(event); text != "" {
+			fmt.Fprintf(&b, "    %s\n", text)
+		}
+	}
+	return b.String()
+}
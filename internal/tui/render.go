@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
 	"oredavids.com/myCal/internal/calendar"
 )
@@ -17,19 +18,25 @@ type RenderData struct {
 	TodayEvents    []*calendar.Event
 	UpcomingEvents []*calendar.Event
 	NextEvent      *calendar.Event
+	Hidden         int                 // events hidden by filters
+	Conflicts      []calendar.Conflict // today's overlapping events
 }
 
 // RenderStatic renders the complete static output
-func RenderStatic(data RenderData) string {
+func (s Styles) RenderStatic(data RenderData) string {
+	if PlainOutput {
+		return renderPlainStatic(data)
+	}
+
 	var b strings.Builder
 
 	// Header
-	b.WriteString(renderHeader(data.UserName))
+	b.WriteString(s.renderHeader(data.UserName, data.Conflicts))
 	b.WriteString("\n")
 
 	// Next meeting countdown
 	if data.NextEvent != nil {
-		countdown := RenderCountdown(data.NextEvent)
+		countdown := s.RenderCountdown(data.NextEvent)
 		if countdown != "" {
 			b.WriteString(countdown)
 			b.WriteString("\n")
@@ -38,29 +45,109 @@ func RenderStatic(data RenderData) string {
 
 	// Today's events
 	b.WriteString("\n")
-	b.WriteString(RenderSectionTitle("Today", "🗓"))
+	b.WriteString(s.RenderSectionTitle("Today", "🗓"))
 	b.WriteString("\n")
 	if len(data.TodayEvents) == 0 {
-		b.WriteString(NoEventsStyle.Render("No events remaining today"))
+		b.WriteString(s.NoEvents.Render("No events remaining today"))
 	} else {
-		b.WriteString(RenderEventList(data.TodayEvents, true, -1))
+		b.WriteString(s.RenderEventList(data.TodayEvents, true, -1))
 	}
 	b.WriteString("\n")
 
 	// Upcoming events
 	if len(data.UpcomingEvents) > 0 {
 		b.WriteString("\n")
-		b.WriteString(RenderSectionTitle("Upcoming", "🗓"))
+		b.WriteString(s.RenderSectionTitle("Upcoming", "🗓"))
+		b.WriteString("\n")
+		b.WriteString(s.RenderEventList(data.UpcomingEvents, false, -1))
+		b.WriteString("\n")
+	}
+
+	if data.Hidden > 0 {
+		b.WriteString(s.RenderHiddenCount(data.Hidden))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// RenderNext renders the next event with its countdown, or a placeholder
+func (s Styles) RenderNext(event *calendar.Event) string {
+	if PlainOutput {
+		return renderPlainNext(event)
+	}
+	if event == nil {
+		return s.NoEvents.Render("No upcoming meetings")
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		s.RenderCountdown(event),
+		s.WithDetails().RenderEventList([]*calendar.Event{event}, false, -1),
+	)
+}
+
+// RenderAgenda renders events grouped by day for the given number of days
+func (s Styles) RenderAgenda(events []*calendar.Event, from time.Time, days int) string {
+	if PlainOutput {
+		return renderPlainAgenda(events, from, days)
+	}
+
+	var b strings.Builder
+
+	shown := 0
+	for i := 0; i < days; i++ {
+		dayStart := from.AddDate(0, 0, i)
+		dayEnd := dayStart.AddDate(0, 0, 1)
+
+		dayEvents := eventsBetween(events, dayStart, dayEnd)
+		if len(dayEvents) == 0 {
+			continue
+		}
+
+		b.WriteString(s.RenderSectionTitle(agendaDayTitle(dayStart), "🗓"))
+		b.WriteString("\n")
+		b.WriteString(s.WithDay(dayStart).RenderEventList(dayEvents, true, -1))
 		b.WriteString("\n")
-		b.WriteString(RenderEventList(data.UpcomingEvents, false, -1))
+		shown++
+	}
+
+	if shown == 0 {
+		b.WriteString(s.NoEvents.Render(fmt.Sprintf("No events in the next %d days", days)))
 		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
-// renderHeader returns the styled header with date, time, and greeting
-func renderHeader(userName string) string {
+// eventsBetween returns the events that take place at any time in [from, to)
+func eventsBetween(events []*calendar.Event, from time.Time, to time.Time) []*calendar.Event {
+	var matched []*calendar.Event
+	for _, event := range events {
+		if event.Overlaps(from, to) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// agendaDayTitle returns "Today", "Tomorrow" or the date for an agenda section
+func agendaDayTitle(day time.Time) string {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch {
+	case day.Equal(today):
+		return "Today"
+	case day.Equal(today.AddDate(0, 0, 1)):
+		return "Tomorrow"
+	default:
+		return day.Format("Monday, January 2")
+	}
+}
+
+// renderHeader returns the styled header with date, time, greeting and
+// today's conflicts
+func (s Styles) renderHeader(userName string, conflicts []calendar.Conflict) string {
 	now := time.Now()
 	dateStr := now.Format("Monday, January 2, 2006 · 3:04 PM")
 
@@ -71,19 +158,22 @@ func renderHeader(userName string) string {
 		greeting = fmt.Sprintf("%s!", getGreeting())
 	}
 
-	header := lipgloss.JoinVertical(
-		lipgloss.Left,
-		DateStyle.Render("  "+dateStr),
-		GreetingStyle.Render("  "+greeting),
-	)
-
-	headerBox := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(PrimaryColor).
-		Padding(0, 1).
-		Render(header)
+	rows := []string{
+		s.Date.Render(s.truncate("  "+dateStr, s.contentWidth())),
+		s.Greeting.Render(s.truncate("  "+greeting, s.contentWidth())),
+	}
+	if len(WorldClock) > 0 {
+		rows = append(rows, s.Label.Render(s.truncate("  🌐 "+worldClockText(), s.contentWidth())))
+	}
+	for _, line := range conflictLines(conflicts) {
+		rows = append(rows, s.Warning.Render(s.truncate("  "+line, s.contentWidth())))
+	}
+	header := lipgloss.JoinVertical(lipgloss.Left, rows...)
 
-	return headerBox
+	if s.width > 0 {
+		return s.Header.Width(s.width - 2).Render(header)
+	}
+	return s.Header.Render(header)
 }
 
 // GetUserName returns the current user's name (exported for main.go)
@@ -92,87 +182,390 @@ func GetUserName() string {
 }
 
 // RenderSectionTitle returns a styled section title
-func RenderSectionTitle(title string, icon string) string {
-	return SectionTitleStyle.Render(icon + "  " + title)
+func (s Styles) RenderSectionTitle(title string, icon string) string {
+	return s.SectionTitle.Render(icon + "  " + title)
 }
 
 // RenderEventList renders a list of events in a styled box
-func RenderEventList(events []*calendar.Event, isToday bool, selectedIndex int) string {
+func (s Styles) RenderEventList(events []*calendar.Event, isToday bool, selectedIndex int) string {
 	if len(events) == 0 {
-		return NoEventsStyle.Render("No events")
+		return s.NoEvents.Render("No events")
+	}
+
+	box := s.EventBox
+	dividerWidth := 33
+	if s.width > 0 {
+		box = box.Width(s.width - 2)
+		dividerWidth = s.contentWidth()
 	}
 
 	var eventRows []string
 
 	for i, event := range events {
-		eventRow := RenderEvent(event, isToday, i == selectedIndex)
+		eventRow := s.RenderEvent(event, isToday, i == selectedIndex)
 		eventRows = append(eventRows, eventRow)
 
 		// Add divider between events (but not after last one)
 		if i < len(events)-1 {
-			divider := DividerStyle.Render("─────────────────────────────────")
+			divider := s.Divider.Render(strings.Repeat("─", dividerWidth))
 			eventRows = append(eventRows, divider)
 		}
 	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left, eventRows...)
-	return EventBoxStyle.Render(content)
+	return box.Render(content)
+}
+
+// lineSpan is the first and last line an event occupies in rendered output
+type lineSpan struct {
+	first, last int
+}
+
+// eventLines returns the lines each event occupies in the output of
+// RenderEventList, offset by the line the list starts on
+func (s Styles) eventLines(events []*calendar.Event, isToday bool, selectedIndex int, start int) []lineSpan {
+	spans := make([]lineSpan, len(events))
+	line := start + 1 // top border
+	for i, event := range events {
+		height := lipgloss.Height(s.RenderEvent(event, isToday, i == selectedIndex))
+		spans[i] = lineSpan{line, line + height - 1}
+		line += height + 1 // divider
+	}
+	return spans
+}
+
+// joinLabel returns the label of an event's meeting link, naming the
+// provider when it is known, e.g. "[Zoom]"
+func joinLabel(event *calendar.Event) string {
+	if event.MeetingProvider != "" {
+		return "[" + event.MeetingProvider + "]"
+	}
+	return "[Join]"
+}
+
+// linkAt returns the URL of the meeting or [Cal] label at the given column of
+// an event's time row, where column 0 is the event box's left border
+func (s Styles) linkAt(event *calendar.Event, isToday bool, column int) string {
+	col := 2 + lipgloss.Width(eventTimeText(event, isToday, s.listDay(isToday))) + 2 // border, padding and gap
+	if event.MeetingURL != "" {
+		width := lipgloss.Width(joinLabel(event))
+		if column >= col && column < col+width {
+			return event.MeetingURL
+		}
+		col += width + 1
+	}
+	if event.HtmlLink != "" && column >= col && column < col+len("[Cal]") {
+		return event.HtmlLink
+	}
+	return ""
+}
+
+// contentWidth returns the width available inside an event box, or 0 when
+// the styles aren't sized to a terminal
+func (s Styles) contentWidth() int {
+	if s.width <= 0 {
+		return 0
+	}
+	return max(s.width-4, 10)
+}
+
+// truncate shortens each line of str to width cells, ending cut lines with an
+// ellipsis; a width of 0 leaves str unchanged
+func (s Styles) truncate(str string, width int) string {
+	if width <= 0 {
+		return str
+	}
+	lines := strings.Split(str, "\n")
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, width, "…")
+	}
+	return strings.Join(lines, "\n")
 }
 
 // RenderEvent renders a single event with styling
-func RenderEvent(event *calendar.Event, isToday bool, selected bool) string {
+func (s Styles) RenderEvent(event *calendar.Event, isToday bool, selected bool) string {
 	var rows []string
 
 	// Title
 	title := event.Summary
-	if selected {
-		title = SelectedStyle.Render("▸ " + title)
-	} else {
-		title = EventTitleStyle.Render(title)
+	switch {
+	case s.highlight != "" && selected:
+		base := s.Selected.UnsetPadding()
+		title = base.Render(" ▸ ") + s.highlightMatches(s.truncate(title, s.contentWidth()-4), event.Summary, base) + base.Render(" ")
+	case s.highlight != "":
+		title = s.highlightMatches(s.truncate(title, s.contentWidth()), event.Summary, s.EventTitle)
+	case selected:
+		// The marker and the Selected padding take four cells
+		title = s.Selected.Render("▸ " + s.truncate(title, s.contentWidth()-4))
+	case event.Hidden || event.HasEnded():
+		// Dim finished events, and hidden ones the TUI was asked to show
+		title = s.Label.Render(s.truncate(title, s.contentWidth()))
+	default:
+		title = s.EventTitle.Render(s.truncate(title, s.contentWidth()))
 	}
 	rows = append(rows, title)
 
 	// Time
 	var timeStr string
-	if event.IsAllDay {
-		timeStr = EventAllDayStyle.Render(event.StartTime.Format("Monday") + " · All day")
-	} else {
-		var formattedTime string
-		if isToday {
-			formattedTime = event.StartTime.Format("3:04 PM")
-		} else {
-			formattedTime = event.StartTime.Format("Mon · 3:04 PM")
-		}
-		timeStr = EventTimeStyle.Render(formattedTime)
+	switch {
+	case event.IsAllDay:
+		timeStr = s.EventAllDay.Render(eventTimeText(event, isToday, s.listDay(isToday)))
+	case event.HasEnded():
+		timeStr = s.Label.Render(eventTimeText(event, isToday, s.listDay(isToday)))
+	default:
+		timeStr = s.EventTime.Render(eventTimeText(event, isToday, s.listDay(isToday)))
 	}
 
-	if HyperlinkSupport {
-		// Terminals with hyperlink support: compact clickable links
+	if HyperlinkSupport || s.clickable {
+		// Terminals with hyperlink support or mouse clicks: compact clickable links
 		var links []string
 		if event.MeetingURL != "" {
-			links = append(links, RenderLink("[Join]", event.MeetingURL, "green"))
+			links = append(links, s.RenderLink(joinLabel(event), event.MeetingURL, s.JoinLink))
 		}
 		if event.HtmlLink != "" {
-			links = append(links, RenderLink("[Cal]", event.HtmlLink, "gray"))
+			links = append(links, s.RenderLink("[Cal]", event.HtmlLink, s.CalLink))
 		}
 		infoRow := timeStr
 		if len(links) > 0 {
 			infoRow += "  " + strings.Join(links, " ")
 		}
-		rows = append(rows, infoRow)
+		rows = append(rows, s.truncate(infoRow, s.contentWidth()))
 	} else {
 		// Terminals without hyperlink support: show URL on separate line
 		rows = append(rows, timeStr)
 		if event.MeetingURL != "" {
-			rows = append(rows, RenderFallbackURL(event.MeetingURL))
+			rows = append(rows, s.RenderFallbackURL(event.MeetingURL))
+		}
+	}
+
+	if text := conflictText(event); text != "" {
+		rows = append(rows, s.Warning.Render(s.truncate(text, s.contentWidth())))
+	}
+
+	if !event.IsAllDay && event.InProgress() {
+		rows = append(rows, s.renderProgress(event))
+	}
+
+	// Selected events show their dial-in details
+	if selected || s.details {
+		for _, line := range meetingDetailLines(event) {
+			rows = append(rows, s.Label.Render(s.truncate(line, s.contentWidth())))
 		}
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
+// highlightMatches renders text (original, possibly truncated) in the base
+// style with the characters matching the search query in the Match style
+func (s Styles) highlightMatches(text string, original string, base lipgloss.Style) string {
+	matched := calendar.MatchIndexes(original, s.highlight)
+	match := s.Match.Inherit(base)
+	origRunes := []rune(original)
+
+	// Render runs of matched and unmatched characters
+	var b strings.Builder
+	var run []rune
+	runMatched := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMatched {
+			b.WriteString(match.Render(string(run)))
+		} else {
+			b.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(text) {
+		isMatch := matched[i] && i < len(origRunes) && origRunes[i] == r
+		if isMatch != runMatched {
+			flush()
+			runMatched = isMatch
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
+
+// maxDialIns is how many dial-in numbers are listed before "+N more"
+const maxDialIns = 2
+
+// meetingDetailLines returns the meeting ID, passcode and dial-in numbers of
+// an event as unstyled lines
+func meetingDetailLines(event *calendar.Event) []string {
+	var lines []string
+
+	var ids []string
+	if id := event.MeetingID(); id != "" {
+		ids = append(ids, "ID "+id)
+	}
+	if event.Passcode != "" {
+		ids = append(ids, "Passcode "+event.Passcode)
+	}
+	if len(ids) > 0 {
+		lines = append(lines, strings.Join(ids, " · "))
+	}
+
+	for i, dialIn := range event.DialIns {
+		if i == maxDialIns {
+			lines = append(lines, fmt.Sprintf("☎ +%d more numbers", len(event.DialIns)-maxDialIns))
+			break
+		}
+		line := "☎ " + dialIn.Number
+		if dialIn.Region != "" {
+			line += " (" + dialIn.Region + ")"
+		}
+		if dialIn.PIN != "" {
+			line += " · PIN " + dialIn.PIN
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// eventTimeText returns the unstyled time label for an event. day is the day
+// the list shows, used to label multi-day events "Day 2 of 4"; it is zero for
+// lists spanning several days.
+func eventTimeText(event *calendar.Event, isToday bool, day time.Time) string {
+	var n, total int
+	if day.IsZero() {
+		n, total = event.DaySpan(event.StartTime)
+	} else {
+		n, total = event.DaySpan(day)
+	}
+
+	if event.IsAllDay {
+		switch {
+		case total == 1:
+			return event.StartTime.Format("Monday") + " · All day"
+		case day.IsZero():
+			lastDay := event.EndTime.AddDate(0, 0, -1)
+			return fmt.Sprintf("%s–%s · All day (%d days)", event.StartTime.Format("Mon Jan 2"), lastDay.Format("Mon Jan 2"), total)
+		default:
+			return fmt.Sprintf("%s · All day · Day %d of %d", day.Format("Monday"), n, total)
+		}
+	}
+
+	text := timeRangeText(event) + secondaryZoneText(event.StartTime) + organizerZoneText(event)
+	if !isToday {
+		text = event.StartTime.Format("Mon · ") + text
+	}
+	if !day.IsZero() && total > 1 {
+		text = fmt.Sprintf("Day %d of %d · ", n, total) + text
+	}
+	return text
+}
+
+// listDay returns the day an event list shows: the agenda day set with
+// WithDay, today for today's list, or zero for lists spanning several days
+func (s Styles) listDay(isToday bool) time.Time {
+	if !s.day.IsZero() || !isToday {
+		return s.day
+	}
+	return startOfToday()
+}
+
+// startOfToday returns local midnight today
+func startOfToday() time.Time {
+	return startOfDay(time.Now())
+}
+
+// startOfDay returns local midnight on t's day
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// timeRangeText returns an event's start and end time with its duration,
+// e.g. "10:00–10:45 AM (45m)"
+func timeRangeText(event *calendar.Event) string {
+	if !event.EndTime.After(event.StartTime) {
+		return FormatTimeRange(event.StartTime, event.EndTime)
+	}
+	return FormatTimeRange(event.StartTime, event.EndTime) + " (" + formatShortDuration(event.Duration()) + ")"
+}
+
+// FormatTimeRange formats the times from start to end, e.g. "10:00–10:45 AM"
+func FormatTimeRange(start time.Time, end time.Time) string {
+	// Name our zone when other zones are shown beside it
+	endFormat := "3:04 PM"
+	if len(SecondaryZones) > 0 {
+		endFormat = "3:04 PM MST"
+	}
+	if !end.After(start) {
+		return start.Format(endFormat)
+	}
+
+	switch {
+	case start.YearDay() != end.YearDay() || start.Year() != end.Year():
+		return start.Format("3:04 PM") + "–" + end.Format("Mon "+endFormat)
+	case start.Format("PM") == end.Format("PM"):
+		return start.Format("3:04") + "–" + end.Format(endFormat)
+	default:
+		return start.Format("3:04 PM") + "–" + end.Format(endFormat)
+	}
+}
+
+// formatShortDuration formats a duration compactly, e.g. "45m" or "1h 30m"
+func formatShortDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// renderProgress renders a bar of how much of a running event has elapsed
+func (s Styles) renderProgress(event *calendar.Event) string {
+	label := s.Countdown.Render("● now")
+	left := s.Label.Render(formatShortDuration(time.Until(event.EndTime)) + " left")
+
+	width := 20
+	if s.width > 0 {
+		width = min(width, s.contentWidth()-lipgloss.Width(label)-lipgloss.Width(left)-2)
+	}
+	if width < 5 {
+		return label + " " + left
+	}
+
+	filled := int(event.Progress() * float64(width))
+	bar := s.Progress.Render(strings.Repeat("━", filled)) + s.Divider.Render(strings.Repeat("━", width-filled))
+	return label + " " + bar + " " + left
+}
+
+// RenderHiddenCount renders how many events the filters hid
+func (s Styles) RenderHiddenCount(hidden int) string {
+	if PlainOutput {
+		return hiddenCountText(hidden)
+	}
+	return s.NoEvents.Render(hiddenCountText(hidden))
+}
+
+// hiddenCountText returns the unstyled hidden event count
+func hiddenCountText(hidden int) string {
+	if hidden == 1 {
+		return "1 event hidden by filters"
+	}
+	return fmt.Sprintf("%d events hidden by filters", hidden)
+}
+
 // RenderCountdown renders the next meeting countdown
-func RenderCountdown(event *calendar.Event) string {
+func (s Styles) RenderCountdown(event *calendar.Event) string {
 	if event == nil {
 		return ""
 	}
@@ -183,9 +576,9 @@ func RenderCountdown(event *calendar.Event) string {
 	}
 
 	return fmt.Sprintf("%s %s %s",
-		LabelStyle.Render("Next:"),
-		EventTitleStyle.Render(event.Summary),
-		CountdownStyle.Render(FormatDuration(duration)),
+		s.Label.Render("Next:"),
+		s.EventTitle.Render(event.Summary),
+		s.Countdown.Render(FormatDuration(duration)),
 	)
 }
 
@@ -224,8 +617,36 @@ func FormatDuration(d time.Duration) string {
 }
 
 // RenderHelp renders the help text
-func RenderHelp() string {
-	return HelpStyle.Render("↑/↓ navigate • enter join • r refresh • q quit")
+func (s Styles) RenderHelp() string {
+	return s.Help.Render(s.truncate("↑/↓ navigate • enter join • c/i/p copy link/ID/passcode • / search • h hidden • x cancel auto-join • f free time • r refresh • t theme • q quit", s.width))
+}
+
+// RenderSearchHelp renders the help text shown with search results
+func (s Styles) RenderSearchHelp() string {
+	return s.Help.Render(s.truncate("n/N next/previous • enter join • / edit search • esc clear • q quit", s.width))
+}
+
+// RenderSearchPrompt renders the search query being typed
+func (s Styles) RenderSearchPrompt(query string) string {
+	prompt := s.Label.Render("/ ") + s.EventTitle.Render(query) + s.Countdown.Render("█")
+	return s.Help.Render(s.truncate(prompt, s.width))
+}
+
+// RenderThemePicker renders the theme list with a swatch for each theme
+func (s Styles) RenderThemePicker(names []string, selectedIndex int) string {
+	rows := []string{s.SectionTitle.UnsetMarginTop().Render("Theme")}
+	for i, name := range names {
+		row := RenderSwatch(Themes[name]) + " " + name
+		if i == selectedIndex {
+			row = s.Selected.Render("▸ " + row)
+		} else {
+			row = s.EventTitle.UnsetBold().Render("  " + row)
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows, s.Help.Render("↑/↓ preview • enter save • esc cancel"))
+
+	return s.EventBox.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
 }
 
 func getGreeting() string {
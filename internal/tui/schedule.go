@@ -0,0 +1,169 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"oredavids.com/myCal/internal/calendar"
+)
+
+// RenderParticipants lists who a meeting is being scheduled with and their
+// time zones, e.g. "you (Lisbon) · ana@example.com (London)"
+func (s Styles) RenderParticipants(participants []calendar.Participant) string {
+	names := make([]string, 0, len(participants))
+	for _, p := range participants {
+		name := participantName(p.Email)
+		if p.Zone != nil && p.Zone.String() != "Local" {
+			name += " (" + zoneCity(p.Zone.String()) + ")"
+		}
+		names = append(names, name)
+	}
+	text := strings.Join(names, " · ")
+	if PlainOutput {
+		return text
+	}
+	return s.Label.Render(s.truncate(text, s.width))
+}
+
+// participantName returns how a participant is shown, "you" for yourself
+func participantName(email string) string {
+	if email == "me" {
+		return "you"
+	}
+	return email
+}
+
+// RenderHeatmap renders one row per day of [from, to) showing, for each step
+// of the working hours, how many participants are busy
+func (s Styles) RenderHeatmap(participants []calendar.Participant, from time.Time, to time.Time, opts calendar.FreeSlotOptions, step time.Duration) string {
+	var b strings.Builder
+	const labelWidth = 8
+
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !opts.Weekends && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+		hours := opts.Hours.On(day)
+
+		// Hour labels above the first row
+		if b.Len() == 0 {
+			header := []rune(strings.Repeat(" ", labelWidth))
+			for t := hours.Start; t.Before(hours.End); t = t.Add(step) {
+				cell := []rune("  ")
+				if t.Minute() == 0 {
+					cell = []rune(fmt.Sprintf("%-2s", t.Format("3")))
+				}
+				header = append(header, cell...)
+			}
+			b.WriteString(s.Label.Render(strings.TrimRight(string(header), " ")) + "\n")
+		}
+
+		b.WriteString(fmt.Sprintf("%-*s", labelWidth, day.Format("Mon 2")))
+		for t := hours.Start; t.Before(hours.End); t = t.Add(step) {
+			b.WriteString(s.heatCell(busyCount(participants, t, t.Add(step)), t.Add(step).Before(time.Now())))
+		}
+		b.WriteString("\n")
+	}
+
+	if PlainOutput {
+		b.WriteString(". everyone free · a number says how many are busy\n")
+	} else {
+		b.WriteString(s.Free.Render("██") + s.Label.Render(" everyone free  ") +
+			s.Warning.Render("██") + s.Label.Render(" 1 busy  ") +
+			s.Busy.Render("██") + s.Label.Render(" 2+ busy") + "\n")
+	}
+	return b.String()
+}
+
+// heatCell renders one step of the heatmap
+func (s Styles) heatCell(busy int, past bool) string {
+	if PlainOutput {
+		switch {
+		case past:
+			return "  "
+		case busy == 0:
+			return ". "
+		default:
+			return fmt.Sprintf("%-2d", busy)
+		}
+	}
+	switch {
+	case past:
+		return s.Divider.Render("··")
+	case busy == 0:
+		return s.Free.Render("██")
+	case busy == 1:
+		return s.Warning.Render("██")
+	default:
+		return s.Busy.Render("██")
+	}
+}
+
+// busyCount returns how many participants are busy at any time in [from, to)
+func busyCount(participants []calendar.Participant, from time.Time, to time.Time) int {
+	count := 0
+	for _, p := range participants {
+		if p.IsBusy(from, to) {
+			count++
+		}
+	}
+	return count
+}
+
+// RenderCandidates renders a numbered list of meeting times with who is busy
+// at each
+func (s Styles) RenderCandidates(candidates []calendar.Candidate) string {
+	if len(candidates) == 0 {
+		if PlainOutput {
+			return "No times in working hours\n"
+		}
+		return s.NoEvents.Render("No times in working hours") + "\n"
+	}
+
+	width := 0
+	for _, c := range candidates {
+		width = max(width, lipgloss.Width(FormatTimeRange(c.Start, c.End)))
+	}
+
+	var b strings.Builder
+	for i, c := range candidates {
+		when := c.Start.Format("Mon Jan 2") + "  " + fmt.Sprintf("%-*s", width, FormatTimeRange(c.Start, c.End))
+		note := candidateNote(c)
+		if PlainOutput {
+			fmt.Fprintf(&b, "%2d  %s  %s\n", i+1, when, note)
+			continue
+		}
+
+		style := s.Free
+		if len(c.Busy) > 0 {
+			style = s.Warning
+		}
+		fmt.Fprintf(&b, "%s  %s  %s\n", s.Label.Render(fmt.Sprintf("%2d", i+1)), s.EventTime.Render(when), style.Render(note))
+	}
+	return b.String()
+}
+
+// candidateNote says who is busy at a candidate time and how far it falls
+// outside working hours
+func candidateNote(c calendar.Candidate) string {
+	note := "everyone free"
+	if len(c.Busy) > 0 {
+		note = "busy: " + participantNames(c.Busy)
+	}
+	if len(c.Outside) > 0 {
+		note += " · outside working hours for " + participantNames(c.Outside)
+	}
+	return note
+}
+
+// participantNames lists participants by how they are shown
+func participantNames(emails []string) string {
+	names := make([]string, len(emails))
+	for i, email := range emails {
+		names[i] = participantName(email)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,307 @@
+package tui
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"oredavids.com/myCal/internal/calendar"
+)
+
+// barRow is one labelled bar of a chart
+type barRow struct {
+	label string
+	value time.Duration
+	note  string // shown after the value, e.g. "8×"
+}
+
+// maxBarWidth is the widest a chart's bars get
+const maxBarWidth = 30
+
+// maxBarLabel is the widest a chart's labels get before being truncated
+const maxBarLabel = 28
+
+// RenderStats renders the meeting load report as bar charts
+func (s Styles) RenderStats(stats calendar.Stats) string {
+	var b strings.Builder
+
+	last := stats.To.AddDate(0, 0, -1)
+	s.writeStatsTitle(&b, fmt.Sprintf("Meetings %s – %s", stats.From.Format("Mon Jan 2"), last.Format("Mon Jan 2")), "📊")
+	b.WriteString(s.statsText(fmt.Sprintf("%s in %d meetings · %s", formatHours(stats.Total), stats.Meetings, backToBackText(stats))))
+	b.WriteString("\n")
+
+	var days []barRow
+	for _, day := range stats.Days {
+		// Weekends only appear when something happened on them
+		if day.Time == 0 && (day.Start.Weekday() == time.Saturday || day.Start.Weekday() == time.Sunday) {
+			continue
+		}
+		days = append(days, barRow{label: day.Start.Format("Mon Jan 2"), value: day.Time})
+	}
+	s.writeStatsTitle(&b, "Per day", "🗓")
+	b.WriteString(s.renderBars(days))
+
+	if len(stats.Weeks) > 1 {
+		var weeks []barRow
+		for _, week := range stats.Weeks {
+			weeks = append(weeks, barRow{label: "Week of " + week.Start.Format("Jan 2"), value: week.Time})
+		}
+		s.writeStatsTitle(&b, "Per week", "🗓")
+		b.WriteString(s.renderBars(weeks))
+	}
+
+	if len(stats.FocusBlocks) > 0 {
+		var blocks []barRow
+		for _, block := range stats.FocusBlocks {
+			blocks = append(blocks, barRow{label: block.Start.Format("Mon Jan 2") + " " + FormatTimeRange(block.Start, block.End), value: block.Duration()})
+		}
+		s.writeStatsTitle(&b, "Longest focus blocks", "🎯")
+		b.WriteString(s.renderBars(blocks))
+	}
+
+	if len(stats.Recurring) > 0 {
+		s.writeStatsTitle(&b, "Top recurring meetings", "🔁")
+		b.WriteString(s.renderBars(shareRows(stats.Recurring)))
+	}
+
+	if len(stats.Organizers) > 0 {
+		s.writeStatsTitle(&b, "By organizer", "👤")
+		b.WriteString(s.renderBars(shareRows(stats.Organizers)))
+	}
+
+	if len(stats.Calendars) > 1 {
+		s.writeStatsTitle(&b, "By calendar", "📚")
+		b.WriteString(s.renderBars(shareRows(stats.Calendars)))
+	}
+
+	return b.String()
+}
+
+// writeStatsTitle writes a section title, undecorated for plain output
+func (s Styles) writeStatsTitle(b *strings.Builder, title string, icon string) {
+	if PlainOutput {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(title + "\n")
+		return
+	}
+	b.WriteString(s.RenderSectionTitle(title, icon))
+	b.WriteString("\n")
+}
+
+// statsText renders a line of the report in the muted style
+func (s Styles) statsText(text string) string {
+	if PlainOutput {
+		return "  " + text
+	}
+	return s.NoEvents.Render(s.truncate(text, s.width))
+}
+
+// backToBackText describes the back-to-back runs, e.g. "3 back-to-back runs,
+// the longest 4 meetings"
+func backToBackText(stats calendar.Stats) string {
+	switch stats.BackToBack {
+	case 0:
+		return "no back-to-back meetings"
+	case 1:
+		return fmt.Sprintf("1 back-to-back run of %d meetings", stats.LongestRun)
+	default:
+		return fmt.Sprintf("%d back-to-back runs, the longest %d meetings", stats.BackToBack, stats.LongestRun)
+	}
+}
+
+// shareRows turns shares into chart rows noting how many meetings each has
+func shareRows(shares []calendar.Share) []barRow {
+	rows := make([]barRow, 0, len(shares))
+	for _, share := range shares {
+		rows = append(rows, barRow{label: share.Name, value: share.Time, note: fmt.Sprintf("%d×", share.Count)})
+	}
+	return rows
+}
+
+// renderBars renders a horizontal bar chart, scaled to its largest value
+func (s Styles) renderBars(rows []barRow) string {
+	labelWidth, largest := 0, time.Duration(0)
+	for _, row := range rows {
+		labelWidth = max(labelWidth, min(lipgloss.Width(row.label), maxBarLabel))
+		largest = max(largest, row.value)
+	}
+
+	barWidth := maxBarWidth
+	if s.width > 0 {
+		// Leave room for the indent, the label and a value like "12h 30m 10×"
+		barWidth = min(barWidth, s.width-labelWidth-16)
+	}
+	barWidth = max(barWidth, 5)
+
+	var b strings.Builder
+	for _, row := range rows {
+		label := s.truncate(row.label, maxBarLabel)
+		label += strings.Repeat(" ", labelWidth-lipgloss.Width(label))
+
+		bar := ""
+		if largest > 0 {
+			bar = barText(float64(row.value) / float64(largest) * float64(barWidth))
+		}
+		if bar != "" {
+			bar += " "
+		}
+		value := formatHours(row.value)
+		if row.note != "" {
+			value += " " + row.note
+		}
+
+		if PlainOutput {
+			fmt.Fprintf(&b, "  %s  %s%s\n", label, bar, value)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s  %s%s\n", s.EventTime.Render(label), s.Bar.Render(bar), s.Label.Render(value))
+	}
+	return b.String()
+}
+
+// barText draws a bar cells long, using eighth blocks for the remainder
+func barText(cells float64) string {
+	eighths := int(cells*8 + 0.5)
+	bar := strings.Repeat("█", eighths/8)
+	if rest := eighths % 8; rest > 0 {
+		bar += string([]rune("▏▎▍▌▋▊▉")[rest-1])
+	}
+	return bar
+}
+
+// jsonStats is the meeting load report in JSON output, with times in minutes
+type jsonStats struct {
+	From        time.Time        `json:"from"`
+	To          time.Time        `json:"to"`
+	Meetings    int              `json:"meetings"`
+	Minutes     int              `json:"minutes"`
+	Days        []jsonPeriod     `json:"days"`
+	Weeks       []jsonPeriod     `json:"weeks"`
+	FocusBlocks []jsonFocusBlock `json:"focusBlocks"`
+	BackToBack  jsonBackToBack   `json:"backToBack"`
+	Recurring   []jsonShare      `json:"recurring"`
+	Organizers  []jsonShare      `json:"organizers"`
+	Calendars   []jsonShare      `json:"calendars"`
+}
+
+// jsonPeriod is a day or week in JSON output
+type jsonPeriod struct {
+	Start   time.Time `json:"start"`
+	Minutes int       `json:"minutes"`
+}
+
+// jsonFocusBlock is a focus block in JSON output
+type jsonFocusBlock struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Minutes int       `json:"minutes"`
+}
+
+// jsonBackToBack is the back-to-back runs in JSON output
+type jsonBackToBack struct {
+	Runs    int `json:"runs"`
+	Longest int `json:"longest"`
+}
+
+// jsonShare is a recurring meeting, organizer or calendar in JSON output
+type jsonShare struct {
+	Name     string `json:"name"`
+	Minutes  int    `json:"minutes"`
+	Meetings int    `json:"meetings"`
+}
+
+// RenderStatsJSON renders the meeting load report as JSON
+func RenderStatsJSON(stats calendar.Stats) (string, error) {
+	output := jsonStats{
+		From:        stats.From,
+		To:          stats.To,
+		Meetings:    stats.Meetings,
+		Minutes:     minutes(stats.Total),
+		Days:        jsonPeriods(stats.Days),
+		Weeks:       jsonPeriods(stats.Weeks),
+		FocusBlocks: []jsonFocusBlock{},
+		BackToBack:  jsonBackToBack{Runs: stats.BackToBack, Longest: stats.LongestRun},
+		Recurring:   jsonShares(stats.Recurring),
+		Organizers:  jsonShares(stats.Organizers),
+		Calendars:   jsonShares(stats.Calendars),
+	}
+	for _, block := range stats.FocusBlocks {
+		output.FocusBlocks = append(output.FocusBlocks, jsonFocusBlock{Start: block.Start, End: block.End, Minutes: minutes(block.Duration())})
+	}
+	return marshalJSON(output)
+}
+
+// jsonPeriods converts days or weeks for JSON output
+func jsonPeriods(periods []calendar.Period) []jsonPeriod {
+	converted := make([]jsonPeriod, 0, len(periods))
+	for _, period := range periods {
+		converted = append(converted, jsonPeriod{Start: period.Start, Minutes: minutes(period.Time)})
+	}
+	return converted
+}
+
+// jsonShares converts shares for JSON output
+func jsonShares(shares []calendar.Share) []jsonShare {
+	converted := make([]jsonShare, 0, len(shares))
+	for _, share := range shares {
+		converted = append(converted, jsonShare{Name: share.Name, Minutes: minutes(share.Time), Meetings: share.Count})
+	}
+	return converted
+}
+
+// RenderStatsCSV renders the meeting load report as CSV, one row per figure:
+// section, name, start, end, minutes and meetings
+func RenderStatsCSV(stats calendar.Stats) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	write := func(section string, name string, start time.Time, end time.Time, d time.Duration, count int) {
+		record := []string{section, name, "", "", strconv.Itoa(minutes(d)), strconv.Itoa(count)}
+		if !start.IsZero() {
+			record[2] = start.Format(time.RFC3339)
+		}
+		if !end.IsZero() {
+			record[3] = end.Format(time.RFC3339)
+		}
+		w.Write(record)
+	}
+
+	w.Write([]string{"section", "name", "start", "end", "minutes", "meetings"})
+	write("total", "", stats.From, stats.To, stats.Total, stats.Meetings)
+	for _, day := range stats.Days {
+		write("day", day.Start.Format("2006-01-02"), day.Start, day.Start.AddDate(0, 0, 1), day.Time, 0)
+	}
+	for _, week := range stats.Weeks {
+		write("week", week.Start.Format("2006-01-02"), week.Start, time.Time{}, week.Time, 0)
+	}
+	for _, block := range stats.FocusBlocks {
+		write("focus", "", block.Start, block.End, block.Duration(), 0)
+	}
+	write("back_to_back", "runs", time.Time{}, time.Time{}, 0, stats.BackToBack)
+	write("back_to_back", "longest", time.Time{}, time.Time{}, 0, stats.LongestRun)
+	for _, share := range stats.Recurring {
+		write("recurring", share.Name, time.Time{}, time.Time{}, share.Time, share.Count)
+	}
+	for _, share := range stats.Organizers {
+		write("organizer", share.Name, time.Time{}, time.Time{}, share.Time, share.Count)
+	}
+	for _, share := range stats.Calendars {
+		write("calendar", share.Name, time.Time{}, time.Time{}, share.Time, share.Count)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// minutes returns d in whole minutes
+func minutes(d time.Duration) int {
+	return int(d.Round(time.Minute).Minutes())
+}
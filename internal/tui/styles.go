@@ -1,95 +1,216 @@
 package tui
 
 import (
+	"time"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/savioxavier/termlink"
 )
 
-// Theme colors
-var (
-	PrimaryColor   = lipgloss.Color("#7C3AED") // Purple
-	SecondaryColor = lipgloss.Color("#06B6D4") // Cyan
-	MutedColor     = lipgloss.Color("#6B7280") // Gray
-	TextColor      = lipgloss.Color("#F3F4F6") // Light gray
-	WarningColor   = lipgloss.Color("#F59E0B") // Amber
-	SuccessColor   = lipgloss.Color("#10B981") // Green
-	SelectedBg     = lipgloss.Color("#374151") // Dark gray for selection
-)
+// Styles holds every style the renderers use, derived from a single Theme.
+// Each renderer is a method on Styles, so several themes can render side by
+// side (e.g. for previews).
+type Styles struct {
+	Theme Theme
+
+	Header       lipgloss.Style
+	Date         lipgloss.Style
+	Greeting     lipgloss.Style
+	SectionTitle lipgloss.Style
+	EventBox     lipgloss.Style
+	EventTitle   lipgloss.Style
+	EventTime    lipgloss.Style
+	EventAllDay  lipgloss.Style
+	NoEvents     lipgloss.Style
+	Divider      lipgloss.Style
+	Countdown    lipgloss.Style
+	Label        lipgloss.Style
+	Selected     lipgloss.Style
+	Help         lipgloss.Style
+	Status       lipgloss.Style
+	Error        lipgloss.Style
+	JoinLink     lipgloss.Style
+	CalLink      lipgloss.Style
+	FallbackURL  lipgloss.Style
+	Match        lipgloss.Style
+	Progress     lipgloss.Style
+	Free         lipgloss.Style
+	Warning      lipgloss.Style
+	Busy         lipgloss.Style
+	Bar          lipgloss.Style
+
+	// width is the terminal width the renderers fit their output to; 0
+	// leaves boxes and dividers at their natural size
+	width int
+
+	// clickable shows [Join] and [Cal] labels even without hyperlink support,
+	// for the TUI to open when clicked
+	clickable bool
+
+	// highlight is a search query whose matches are highlighted in titles
+	highlight string
+
+	// day is the day an agenda section shows, for labelling multi-day events
+	day time.Time
+
+	// details shows every event's meeting ID, passcode and dial-in numbers,
+	// not just the selected one's
+	details bool
+}
+
+// NewStyles builds the styles for a theme
+func NewStyles(theme Theme) Styles {
+	return Styles{
+		Theme: theme,
 
-// Styles
-var (
-	DateStyle = lipgloss.NewStyle().
-			Foreground(SecondaryColor).
-			Bold(true)
+		Header: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Primary).
+			Padding(0, 1),
+
+		Date: lipgloss.NewStyle().
+			Foreground(theme.Secondary).
+			Bold(true),
 
-	GreetingStyle = lipgloss.NewStyle().
-			Foreground(TextColor).
-			Italic(true)
+		Greeting: lipgloss.NewStyle().
+			Foreground(theme.Text).
+			Italic(true),
 
-	SectionTitleStyle = lipgloss.NewStyle().
-				Foreground(PrimaryColor).
-				Bold(true).
-				MarginTop(1)
+		SectionTitle: lipgloss.NewStyle().
+			Foreground(theme.Primary).
+			Bold(true).
+			MarginTop(1),
 
-	EventBoxStyle = lipgloss.NewStyle().
+		EventBox: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(MutedColor).
-			Padding(0, 1)
+			BorderForeground(theme.Muted).
+			Padding(0, 1),
 
-	EventTitleStyle = lipgloss.NewStyle().
-			Foreground(TextColor).
-			Bold(true)
+		EventTitle: lipgloss.NewStyle().
+			Foreground(theme.Text).
+			Bold(true),
 
-	EventTimeStyle = lipgloss.NewStyle().
-			Foreground(SecondaryColor)
+		EventTime: lipgloss.NewStyle().
+			Foreground(theme.Secondary),
 
-	EventAllDayStyle = lipgloss.NewStyle().
-				Foreground(WarningColor).
-				Italic(true)
+		EventAllDay: lipgloss.NewStyle().
+			Foreground(theme.Warning).
+			Italic(true),
 
-	NoEventsStyle = lipgloss.NewStyle().
-			Foreground(MutedColor).
+		NoEvents: lipgloss.NewStyle().
+			Foreground(theme.Muted).
 			Italic(true).
-			Padding(0, 1)
+			Padding(0, 1),
 
-	DividerStyle = lipgloss.NewStyle().
-			Foreground(MutedColor)
+		Divider: lipgloss.NewStyle().
+			Foreground(theme.Muted),
 
-	CountdownStyle = lipgloss.NewStyle().
-			Foreground(WarningColor).
-			Bold(true)
+		Countdown: lipgloss.NewStyle().
+			Foreground(theme.Warning).
+			Bold(true),
 
-	LabelStyle = lipgloss.NewStyle().
-			Foreground(MutedColor)
+		Label: lipgloss.NewStyle().
+			Foreground(theme.Muted),
 
-	SelectedStyle = lipgloss.NewStyle().
-			Background(SelectedBg).
-			Foreground(TextColor).
+		Selected: lipgloss.NewStyle().
+			Background(theme.SelectedBg).
+			Foreground(theme.Text).
 			Bold(true).
-			Padding(0, 1)
+			Padding(0, 1),
 
-	HelpStyle = lipgloss.NewStyle().
-			Foreground(MutedColor).
-			MarginTop(1)
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
 
-	StatusStyle = lipgloss.NewStyle().
-			Foreground(SuccessColor).
-			Italic(true)
-)
+		Status: lipgloss.NewStyle().
+			Foreground(theme.Success).
+			Italic(true),
+
+		Error: lipgloss.NewStyle().
+			Foreground(theme.Error),
+
+		JoinLink: lipgloss.NewStyle().
+			Foreground(theme.Accent),
+
+		CalLink: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+
+		FallbackURL: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+
+		Match: lipgloss.NewStyle().
+			Foreground(theme.Accent).
+			Underline(true),
+
+		Progress: lipgloss.NewStyle().
+			Foreground(theme.Success),
+
+		Free: lipgloss.NewStyle().
+			Foreground(theme.Success),
+
+		Warning: lipgloss.NewStyle().
+			Foreground(theme.Warning),
+
+		Busy: lipgloss.NewStyle().
+			Foreground(theme.Error),
+
+		Bar: lipgloss.NewStyle().
+			Foreground(theme.Primary),
+	}
+}
+
+// WithWidth returns a copy of the styles that sizes boxes and dividers to the
+// given terminal width and truncates titles that don't fit
+func (s Styles) WithWidth(width int) Styles {
+	s.width = width
+	return s
+}
+
+// WithClickableLinks returns a copy of the styles that always renders the
+// [Join] and [Cal] labels, for views that handle mouse clicks on them
+func (s Styles) WithClickableLinks() Styles {
+	s.clickable = true
+	return s
+}
+
+// WithHighlight returns a copy of the styles that highlights the characters
+// of event titles matching a search query
+func (s Styles) WithHighlight(query string) Styles {
+	s.highlight = query
+	return s
+}
+
+// WithDay returns a copy of the styles for rendering the events of one day,
+// which labels multi-day events with the day of the event it is
+func (s Styles) WithDay(day time.Time) Styles {
+	s.day = day
+	return s
+}
+
+// WithDetails returns a copy of the styles that shows the meeting details of
+// every event
+func (s Styles) WithDetails() Styles {
+	s.details = true
+	return s
+}
 
 // SupportsHyperlinks caches the hyperlink support check
 var HyperlinkSupport = termlink.SupportsHyperlinks()
 
-// RenderLink creates a clickable hyperlink if supported
-func RenderLink(text string, url string, color string) string {
+// RenderLink creates a clickable hyperlink if supported, or a plain label the
+// TUI opens on click when the styles are clickable
+func (s Styles) RenderLink(text string, url string, style lipgloss.Style) string {
 	if HyperlinkSupport {
-		return termlink.ColorLink(text, url, color)
+		return termenv.Hyperlink(url, style.Render(text))
+	}
+	if s.clickable {
+		return style.Render(text)
 	}
 	return ""
 }
 
 // RenderFallbackURL returns a styled URL for terminals without hyperlink support
-func RenderFallbackURL(url string) string {
-	style := lipgloss.NewStyle().Foreground(MutedColor)
-	return style.Render("  ↳ " + url)
+func (s Styles) RenderFallbackURL(url string) string {
+	return s.FallbackURL.Render(s.truncate("  ↳ "+url, s.contentWidth()))
 }
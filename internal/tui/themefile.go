@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// themeFile is the on-disk format of a user theme. Every color is required
+// unless the theme inherits from another one, in which case only overrides
+// need to be given. Background is optional, and a missing error color
+// defaults to the warning color. The 256- and 16-color fallbacks are derived
+// from each color unless set explicitly in "ansi256" and "ansi", keyed by
+// color name.
+type themeFile struct {
+	Name       string `json:"name"`
+	Inherits   string `json:"inherits"`
+	Primary    string `json:"primary"`
+	Secondary  string `json:"secondary"`
+	Accent     string `json:"accent"`
+	Muted      string `json:"muted"`
+	Text       string `json:"text"`
+	Warning    string `json:"warning"`
+	Success    string `json:"success"`
+	Error      string `json:"error"`
+	Background string `json:"background"`
+	SelectedBg string `json:"selectedBg"`
+
+	ANSI256 map[string]string `json:"ansi256"`
+	ANSI    map[string]string `json:"ansi"`
+}
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// LoadUserThemes adds every *.json theme in dir to Themes, keyed by file name.
+// Invalid files are skipped and reported in the returned error; a missing
+// directory simply has no themes.
+func LoadUserThemes(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, path := range paths {
+		key := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		theme, err := loadThemeFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("theme %s: %v", path, err))
+			continue
+		}
+		if existing, ok := Themes[key]; ok && existing.File == "" {
+			errs = append(errs, fmt.Errorf("theme %s: name %q is already used by a built-in theme", path, key))
+			continue
+		}
+		Themes[key] = theme
+	}
+	return errors.Join(errs...)
+}
+
+// loadThemeFile reads, resolves and validates a single theme file
+func loadThemeFile(path string) (Theme, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	var f themeFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return Theme{}, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	var theme Theme
+	if f.Inherits != "" {
+		base, ok := Themes[f.Inherits]
+		if !ok || base.File != "" {
+			return Theme{}, fmt.Errorf("inherits unknown built-in theme %q", f.Inherits)
+		}
+		theme = base
+	}
+
+	// Only the colors are inherited; the name defaults to the file's key so
+	// the theme isn't listed under its base theme's name
+	theme.File = path
+	theme.Name = f.Name
+	if theme.Name == "" {
+		theme.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	fields := []struct {
+		name   string
+		value  string
+		target *lipgloss.CompleteColor
+	}{
+		{"primary", f.Primary, &theme.Primary},
+		{"secondary", f.Secondary, &theme.Secondary},
+		{"accent", f.Accent, &theme.Accent},
+		{"muted", f.Muted, &theme.Muted},
+		{"text", f.Text, &theme.Text},
+		{"warning", f.Warning, &theme.Warning},
+		{"success", f.Success, &theme.Success},
+		{"error", f.Error, &theme.Error},
+		{"background", f.Background, &theme.Background},
+		{"selectedBg", f.SelectedBg, &theme.SelectedBg},
+	}
+
+	var missing []string
+	known := make(map[string]bool)
+	for _, field := range fields {
+		known[field.name] = true
+		if field.value != "" {
+			if !validColor(field.value) {
+				return Theme{}, fmt.Errorf("%s: %q is not a hex color (#RGB or #RRGGBB) or ANSI color number (0-255)", field.name, field.value)
+			}
+			*field.target = completeColor(field.value)
+		}
+		if field.target.TrueColor == "" && field.name != "background" && field.name != "error" {
+			missing = append(missing, field.name)
+		}
+	}
+	if theme.Error.TrueColor == "" {
+		theme.Error = theme.Warning
+	}
+	if len(missing) > 0 {
+		return Theme{}, fmt.Errorf("missing colors: %s (set them or use \"inherits\")", strings.Join(missing, ", "))
+	}
+
+	// Explicit fallbacks override the derived ones
+	for _, field := range fields {
+		if value, ok := f.ANSI256[field.name]; ok {
+			if !validANSI(value, 255) {
+				return Theme{}, fmt.Errorf("ansi256.%s: %q is not a color number (0-255)", field.name, value)
+			}
+			field.target.ANSI256 = value
+		}
+		if value, ok := f.ANSI[field.name]; ok {
+			if !validANSI(value, 15) {
+				return Theme{}, fmt.Errorf("ansi.%s: %q is not a color number (0-15)", field.name, value)
+			}
+			field.target.ANSI = value
+		}
+	}
+	for _, fallbacks := range []map[string]string{f.ANSI256, f.ANSI} {
+		for name := range fallbacks {
+			if !known[name] {
+				return Theme{}, fmt.Errorf("unknown color %q in fallbacks", name)
+			}
+		}
+	}
+
+	return theme, nil
+}
+
+// validColor reports whether s is a color lipgloss understands
+func validColor(s string) bool {
+	return hexColorPattern.MatchString(s) || validANSI(s, 255)
+}
+
+// validANSI reports whether s is a color number between 0 and max
+func validANSI(s string, max int) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= max
+}
+
+// completeColor builds a color from a hex or ANSI value, deriving the 256- and
+// 16-color fallbacks by picking the nearest palette entries
+func completeColor(value string) lipgloss.CompleteColor {
+	if len(value) == 4 && value[0] == '#' {
+		value = string([]byte{'#', value[1], value[1], value[2], value[2], value[3], value[3]})
+	}
+
+	c := termenv.TrueColor.Color(value)
+	return lipgloss.CompleteColor{
+		TrueColor: value,
+		ANSI256:   colorIndex(termenv.ANSI256.Convert(c)),
+		ANSI:      colorIndex(termenv.ANSI.Convert(c)),
+	}
+}
+
+// colorIndex returns the palette index of an ANSI color as a string
+func colorIndex(c termenv.Color) string {
+	switch c := c.(type) {
+	case termenv.ANSI256Color:
+		return strconv.Itoa(int(c))
+	case termenv.ANSIColor:
+		return strconv.Itoa(int(c))
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTheme writes a theme file named key.json in dir
+func writeTheme(t *testing.T, dir string, key string, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, key+".json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadThemeFile(t *testing.T) {
+	dir := t.TempDir()
+	complete := `"primary": "#112233", "secondary": "#445566", "accent": "#778899", "muted": "#aabbcc",
+		"text": "#ddeeff", "warning": "#f00", "success": "2", "selectedBg": "#000000"`
+
+	t.Run("complete", func(t *testing.T) {
+		theme, err := loadThemeFile(writeTheme(t, dir, "plain", `{`+complete+`}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if theme.Name != "plain" {
+			t.Errorf("Name = %q, want the file's key", theme.Name)
+		}
+		if theme.Warning.TrueColor != "#ff0000" {
+			t.Errorf("Warning = %q, want the short hex expanded", theme.Warning.TrueColor)
+		}
+		if theme.Error != theme.Warning {
+			t.Errorf("Error = %v, want the warning color", theme.Error)
+		}
+	})
+
+	t.Run("inherits", func(t *testing.T) {
+		theme, err := loadThemeFile(writeTheme(t, dir, "sunset", `{"inherits": "dracula", "primary": "#FF7A59"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if theme.Name != "sunset" {
+			t.Errorf("Name = %q, want the file's key rather than the base theme's name", theme.Name)
+		}
+		if theme.Primary.TrueColor != "#FF7A59" || theme.Secondary != Themes["dracula"].Secondary {
+			t.Errorf("colors = %v, %v, want the override and the base theme's", theme.Primary, theme.Secondary)
+		}
+		if theme.File == "" {
+			t.Error("File is empty, want the theme's path")
+		}
+	})
+
+	t.Run("named with fallbacks", func(t *testing.T) {
+		theme, err := loadThemeFile(writeTheme(t, dir, "named", `{"name": "Named", `+complete+`, "ansi256": {"primary": "17"}, "ansi": {"primary": "4"}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if theme.Name != "Named" || theme.Primary.ANSI256 != "17" || theme.Primary.ANSI != "4" {
+			t.Errorf("theme = %q %v, want the name and explicit fallbacks", theme.Name, theme.Primary)
+		}
+	})
+
+	errorTests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"invalid JSON", `{`, "invalid JSON"},
+		{"missing colors", `{"primary": "#112233"}`, "missing colors: secondary"},
+		{"bad color", `{"inherits": "dracula", "accent": "blue"}`, "accent"},
+		{"unknown base", `{"inherits": "nope"}`, "unknown built-in theme"},
+		{"bad fallback", `{"inherits": "dracula", "ansi": {"primary": "16"}}`, "ansi.primary"},
+		{"unknown fallback", `{"inherits": "dracula", "ansi256": {"purple": "1"}}`, "unknown color"},
+	}
+	for _, tt := range errorTests {
+		_, err := loadThemeFile(writeTheme(t, dir, "broken", tt.content))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: loadThemeFile() error = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestLoadUserThemesRejectsBuiltInNames(t *testing.T) {
+	dir := t.TempDir()
+	writeTheme(t, dir, "dracula", `{"inherits": "nord"}`)
+	before := Themes["dracula"]
+	if err := LoadUserThemes(dir); err == nil || !strings.Contains(err.Error(), "built-in") {
+		t.Errorf("LoadUserThemes() error = %v, want the built-in name rejected", err)
+	}
+	if Themes["dracula"] != before {
+		t.Error("the built-in theme was replaced")
+	}
+}
@@ -1,180 +1,277 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Theme defines a color scheme
 type Theme struct {
-	Name        string
-	Primary     lipgloss.Color
-	Secondary   lipgloss.Color
-	Accent      lipgloss.Color
-	Muted       lipgloss.Color
-	Text        lipgloss.Color
-	Warning     lipgloss.Color
-	Success     lipgloss.Color
-	Background  lipgloss.Color
-	SelectedBg  lipgloss.Color
+	Name       string
+	Primary    lipgloss.CompleteColor
+	Secondary  lipgloss.CompleteColor
+	Accent     lipgloss.CompleteColor
+	Muted      lipgloss.CompleteColor
+	Text       lipgloss.CompleteColor
+	Warning    lipgloss.CompleteColor
+	Success    lipgloss.CompleteColor
+	Error      lipgloss.CompleteColor
+	Background lipgloss.CompleteColor
+	SelectedBg lipgloss.CompleteColor
+	File       string // theme file path; empty for built-in themes
 }
 
 // Available themes
 var Themes = map[string]Theme{
 	"default": {
 		Name:       "Default",
-		Primary:    lipgloss.Color("#7C3AED"),
-		Secondary:  lipgloss.Color("#06B6D4"),
-		Accent:     lipgloss.Color("#10B981"),
-		Muted:      lipgloss.Color("#6B7280"),
-		Text:       lipgloss.Color("#F3F4F6"),
-		Warning:    lipgloss.Color("#F59E0B"),
-		Success:    lipgloss.Color("#10B981"),
-		SelectedBg: lipgloss.Color("#374151"),
+		Primary:    lipgloss.CompleteColor{TrueColor: "#7C3AED", ANSI256: "99", ANSI: "5"},
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#06B6D4", ANSI256: "38", ANSI: "6"},
+		Accent:     lipgloss.CompleteColor{TrueColor: "#10B981", ANSI256: "36", ANSI: "2"},
+		Muted:      lipgloss.CompleteColor{TrueColor: "#6B7280", ANSI256: "243", ANSI: "8"},
+		Text:       lipgloss.CompleteColor{TrueColor: "#F3F4F6", ANSI256: "255", ANSI: "15"},
+		Warning:    lipgloss.CompleteColor{TrueColor: "#F59E0B", ANSI256: "214", ANSI: "3"},
+		Success:    lipgloss.CompleteColor{TrueColor: "#10B981", ANSI256: "36", ANSI: "2"},
+		Error:      lipgloss.CompleteColor{TrueColor: "#EF4444", ANSI256: "203", ANSI: "9"},
+		Background: lipgloss.CompleteColor{TrueColor: "#111827", ANSI256: "234", ANSI: "0"},
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#374151", ANSI256: "238", ANSI: "8"},
 	},
 	"catppuccin": {
 		Name:       "Catppuccin",
-		Primary:    lipgloss.Color("#CBA6F7"), // Mauve
-		Secondary:  lipgloss.Color("#89DCEB"), // Sky
-		Accent:     lipgloss.Color("#A6E3A1"), // Green
-		Muted:      lipgloss.Color("#6C7086"), // Overlay0
-		Text:       lipgloss.Color("#CDD6F4"), // Text
-		Warning:    lipgloss.Color("#F9E2AF"), // Yellow
-		Success:    lipgloss.Color("#A6E3A1"), // Green
-		SelectedBg: lipgloss.Color("#45475A"), // Surface0
+		Primary:    lipgloss.CompleteColor{TrueColor: "#CBA6F7", ANSI256: "183", ANSI: "13"}, // Mauve
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#89DCEB", ANSI256: "116", ANSI: "14"}, // Sky
+		Accent:     lipgloss.CompleteColor{TrueColor: "#A6E3A1", ANSI256: "151", ANSI: "10"}, // Green
+		Muted:      lipgloss.CompleteColor{TrueColor: "#6C7086", ANSI256: "243", ANSI: "8"},  // Overlay0
+		Text:       lipgloss.CompleteColor{TrueColor: "#CDD6F4", ANSI256: "189", ANSI: "15"}, // Text
+		Warning:    lipgloss.CompleteColor{TrueColor: "#F9E2AF", ANSI256: "223", ANSI: "11"}, // Yellow
+		Success:    lipgloss.CompleteColor{TrueColor: "#A6E3A1", ANSI256: "151", ANSI: "10"}, // Green
+		Error:      lipgloss.CompleteColor{TrueColor: "#F38BA8", ANSI256: "211", ANSI: "9"},  // Red
+		Background: lipgloss.CompleteColor{TrueColor: "#1E1E2E", ANSI256: "235", ANSI: "0"},  // Base
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#45475A", ANSI256: "239", ANSI: "8"},  // Surface0
 	},
 	"dracula": {
 		Name:       "Dracula",
-		Primary:    lipgloss.Color("#BD93F9"), // Purple
-		Secondary:  lipgloss.Color("#8BE9FD"), // Cyan
-		Accent:     lipgloss.Color("#50FA7B"), // Green
-		Muted:      lipgloss.Color("#6272A4"), // Comment
-		Text:       lipgloss.Color("#F8F8F2"), // Foreground
-		Warning:    lipgloss.Color("#FFB86C"), // Orange
-		Success:    lipgloss.Color("#50FA7B"), // Green
-		SelectedBg: lipgloss.Color("#44475A"), // Current Line
+		Primary:    lipgloss.CompleteColor{TrueColor: "#BD93F9", ANSI256: "141", ANSI: "13"}, // Purple
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#8BE9FD", ANSI256: "117", ANSI: "14"}, // Cyan
+		Accent:     lipgloss.CompleteColor{TrueColor: "#50FA7B", ANSI256: "84", ANSI: "10"},  // Green
+		Muted:      lipgloss.CompleteColor{TrueColor: "#6272A4", ANSI256: "61", ANSI: "8"},   // Comment
+		Text:       lipgloss.CompleteColor{TrueColor: "#F8F8F2", ANSI256: "231", ANSI: "15"}, // Foreground
+		Warning:    lipgloss.CompleteColor{TrueColor: "#FFB86C", ANSI256: "215", ANSI: "11"}, // Orange
+		Success:    lipgloss.CompleteColor{TrueColor: "#50FA7B", ANSI256: "84", ANSI: "10"},  // Green
+		Error:      lipgloss.CompleteColor{TrueColor: "#FF5555", ANSI256: "203", ANSI: "9"},  // Red
+		Background: lipgloss.CompleteColor{TrueColor: "#282A36", ANSI256: "236", ANSI: "0"},  // Background
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#44475A", ANSI256: "239", ANSI: "8"},  // Current Line
 	},
 	"nord": {
 		Name:       "Nord",
-		Primary:    lipgloss.Color("#81A1C1"), // Nord9
-		Secondary:  lipgloss.Color("#88C0D0"), // Nord8
-		Accent:     lipgloss.Color("#A3BE8C"), // Nord14
-		Muted:      lipgloss.Color("#4C566A"), // Nord3
-		Text:       lipgloss.Color("#ECEFF4"), // Nord6
-		Warning:    lipgloss.Color("#EBCB8B"), // Nord13
-		Success:    lipgloss.Color("#A3BE8C"), // Nord14
-		SelectedBg: lipgloss.Color("#3B4252"), // Nord1
+		Primary:    lipgloss.CompleteColor{TrueColor: "#81A1C1", ANSI256: "109", ANSI: "12"}, // Nord9
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#88C0D0", ANSI256: "110", ANSI: "14"}, // Nord8
+		Accent:     lipgloss.CompleteColor{TrueColor: "#A3BE8C", ANSI256: "144", ANSI: "10"}, // Nord14
+		Muted:      lipgloss.CompleteColor{TrueColor: "#4C566A", ANSI256: "240", ANSI: "8"},  // Nord3
+		Text:       lipgloss.CompleteColor{TrueColor: "#ECEFF4", ANSI256: "255", ANSI: "15"}, // Nord6
+		Warning:    lipgloss.CompleteColor{TrueColor: "#EBCB8B", ANSI256: "186", ANSI: "11"}, // Nord13
+		Success:    lipgloss.CompleteColor{TrueColor: "#A3BE8C", ANSI256: "144", ANSI: "10"}, // Nord14
+		Error:      lipgloss.CompleteColor{TrueColor: "#BF616A", ANSI256: "131", ANSI: "1"},  // Nord11
+		Background: lipgloss.CompleteColor{TrueColor: "#2E3440", ANSI256: "236", ANSI: "0"},  // Nord0
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#3B4252", ANSI256: "238", ANSI: "8"},  // Nord1
 	},
 	"tokyonight": {
 		Name:       "Tokyo Night",
-		Primary:    lipgloss.Color("#BB9AF7"), // Purple
-		Secondary:  lipgloss.Color("#7DCFFF"), // Cyan
-		Accent:     lipgloss.Color("#9ECE6A"), // Green
-		Muted:      lipgloss.Color("#565F89"), // Comment
-		Text:       lipgloss.Color("#C0CAF5"), // Foreground
-		Warning:    lipgloss.Color("#E0AF68"), // Yellow
-		Success:    lipgloss.Color("#9ECE6A"), // Green
-		SelectedBg: lipgloss.Color("#292E42"), // BG highlight
+		Primary:    lipgloss.CompleteColor{TrueColor: "#BB9AF7", ANSI256: "141", ANSI: "13"}, // Purple
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#7DCFFF", ANSI256: "117", ANSI: "12"}, // Cyan
+		Accent:     lipgloss.CompleteColor{TrueColor: "#9ECE6A", ANSI256: "149", ANSI: "10"}, // Green
+		Muted:      lipgloss.CompleteColor{TrueColor: "#565F89", ANSI256: "60", ANSI: "8"},   // Comment
+		Text:       lipgloss.CompleteColor{TrueColor: "#C0CAF5", ANSI256: "153", ANSI: "15"}, // Foreground
+		Warning:    lipgloss.CompleteColor{TrueColor: "#E0AF68", ANSI256: "179", ANSI: "11"}, // Yellow
+		Success:    lipgloss.CompleteColor{TrueColor: "#9ECE6A", ANSI256: "149", ANSI: "10"}, // Green
+		Error:      lipgloss.CompleteColor{TrueColor: "#F7768E", ANSI256: "210", ANSI: "9"},  // Red
+		Background: lipgloss.CompleteColor{TrueColor: "#1A1B26", ANSI256: "234", ANSI: "0"},  // BG
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#292E42", ANSI256: "236", ANSI: "8"},  // BG highlight
 	},
 	"gruvbox": {
 		Name:       "Gruvbox",
-		Primary:    lipgloss.Color("#D3869B"), // Purple
-		Secondary:  lipgloss.Color("#83A598"), // Aqua
-		Accent:     lipgloss.Color("#B8BB26"), // Green
-		Muted:      lipgloss.Color("#928374"), // Gray
-		Text:       lipgloss.Color("#EBDBB2"), // FG
-		Warning:    lipgloss.Color("#FABD2F"), // Yellow
-		Success:    lipgloss.Color("#B8BB26"), // Green
-		SelectedBg: lipgloss.Color("#3C3836"), // BG1
+		Primary:    lipgloss.CompleteColor{TrueColor: "#D3869B", ANSI256: "174", ANSI: "9"},  // Purple
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#83A598", ANSI256: "108", ANSI: "8"},  // Aqua
+		Accent:     lipgloss.CompleteColor{TrueColor: "#B8BB26", ANSI256: "142", ANSI: "3"},  // Green
+		Muted:      lipgloss.CompleteColor{TrueColor: "#928374", ANSI256: "102", ANSI: "8"},  // Gray
+		Text:       lipgloss.CompleteColor{TrueColor: "#EBDBB2", ANSI256: "187", ANSI: "15"}, // FG
+		Warning:    lipgloss.CompleteColor{TrueColor: "#FABD2F", ANSI256: "214", ANSI: "3"},  // Yellow
+		Success:    lipgloss.CompleteColor{TrueColor: "#B8BB26", ANSI256: "142", ANSI: "3"},  // Green
+		Error:      lipgloss.CompleteColor{TrueColor: "#FB4934", ANSI256: "203", ANSI: "9"},  // Red
+		Background: lipgloss.CompleteColor{TrueColor: "#282828", ANSI256: "235", ANSI: "0"},  // BG0
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#3C3836", ANSI256: "237", ANSI: "8"},  // BG1
+	},
+	"default-light": {
+		Name:       "Default Light",
+		Primary:    lipgloss.CompleteColor{TrueColor: "#6D28D9", ANSI256: "56", ANSI: "5"},
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#0E7490", ANSI256: "30", ANSI: "6"},
+		Accent:     lipgloss.CompleteColor{TrueColor: "#047857", ANSI256: "29", ANSI: "2"},
+		Muted:      lipgloss.CompleteColor{TrueColor: "#6B7280", ANSI256: "243", ANSI: "8"},
+		Text:       lipgloss.CompleteColor{TrueColor: "#1F2937", ANSI256: "235", ANSI: "0"},
+		Warning:    lipgloss.CompleteColor{TrueColor: "#B45309", ANSI256: "130", ANSI: "3"},
+		Success:    lipgloss.CompleteColor{TrueColor: "#047857", ANSI256: "29", ANSI: "2"},
+		Error:      lipgloss.CompleteColor{TrueColor: "#DC2626", ANSI256: "160", ANSI: "1"},
+		Background: lipgloss.CompleteColor{TrueColor: "#FFFFFF", ANSI256: "231", ANSI: "15"},
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#E5E7EB", ANSI256: "254", ANSI: "7"},
+	},
+	"catppuccin-latte": {
+		Name:       "Catppuccin Latte",
+		Primary:    lipgloss.CompleteColor{TrueColor: "#8839EF", ANSI256: "99", ANSI: "5"},   // Mauve
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#04A5E5", ANSI256: "38", ANSI: "6"},   // Sky
+		Accent:     lipgloss.CompleteColor{TrueColor: "#40A02B", ANSI256: "70", ANSI: "2"},   // Green
+		Muted:      lipgloss.CompleteColor{TrueColor: "#9CA0B0", ANSI256: "247", ANSI: "8"},  // Overlay0
+		Text:       lipgloss.CompleteColor{TrueColor: "#4C4F69", ANSI256: "240", ANSI: "0"},  // Text
+		Warning:    lipgloss.CompleteColor{TrueColor: "#DF8E1D", ANSI256: "172", ANSI: "3"},  // Yellow
+		Success:    lipgloss.CompleteColor{TrueColor: "#40A02B", ANSI256: "70", ANSI: "2"},   // Green
+		Error:      lipgloss.CompleteColor{TrueColor: "#D20F39", ANSI256: "161", ANSI: "1"},  // Red
+		Background: lipgloss.CompleteColor{TrueColor: "#EFF1F5", ANSI256: "255", ANSI: "15"}, // Base
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#CCD0DA", ANSI256: "252", ANSI: "7"},  // Surface0
+	},
+	"gruvbox-light": {
+		Name:       "Gruvbox Light",
+		Primary:    lipgloss.CompleteColor{TrueColor: "#8F3F71", ANSI256: "95", ANSI: "5"},   // Purple
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#427B58", ANSI256: "65", ANSI: "2"},   // Aqua
+		Accent:     lipgloss.CompleteColor{TrueColor: "#79740E", ANSI256: "100", ANSI: "3"},  // Green
+		Muted:      lipgloss.CompleteColor{TrueColor: "#928374", ANSI256: "102", ANSI: "8"},  // Gray
+		Text:       lipgloss.CompleteColor{TrueColor: "#3C3836", ANSI256: "237", ANSI: "0"},  // FG
+		Warning:    lipgloss.CompleteColor{TrueColor: "#B57614", ANSI256: "136", ANSI: "3"},  // Yellow
+		Success:    lipgloss.CompleteColor{TrueColor: "#79740E", ANSI256: "100", ANSI: "3"},  // Green
+		Error:      lipgloss.CompleteColor{TrueColor: "#9D0006", ANSI256: "124", ANSI: "1"},  // Red
+		Background: lipgloss.CompleteColor{TrueColor: "#FBF1C7", ANSI256: "230", ANSI: "15"}, // BG0
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#EBDBB2", ANSI256: "187", ANSI: "7"},  // BG1
+	},
+	"solarized-dark": {
+		Name:       "Solarized Dark",
+		Primary:    lipgloss.CompleteColor{TrueColor: "#6C71C4", ANSI256: "62", ANSI: "4"},   // Violet
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#2AA198", ANSI256: "36", ANSI: "6"},   // Cyan
+		Accent:     lipgloss.CompleteColor{TrueColor: "#859900", ANSI256: "100", ANSI: "3"},  // Green
+		Muted:      lipgloss.CompleteColor{TrueColor: "#586E75", ANSI256: "242", ANSI: "8"},  // Base01
+		Text:       lipgloss.CompleteColor{TrueColor: "#93A1A1", ANSI256: "247", ANSI: "15"}, // Base1
+		Warning:    lipgloss.CompleteColor{TrueColor: "#B58900", ANSI256: "136", ANSI: "3"},  // Yellow
+		Success:    lipgloss.CompleteColor{TrueColor: "#859900", ANSI256: "100", ANSI: "3"},  // Green
+		Error:      lipgloss.CompleteColor{TrueColor: "#DC322F", ANSI256: "160", ANSI: "1"},  // Red
+		Background: lipgloss.CompleteColor{TrueColor: "#002B36", ANSI256: "234", ANSI: "0"},  // Base03
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#073642", ANSI256: "235", ANSI: "8"},  // Base02
+	},
+	"solarized-light": {
+		Name:       "Solarized Light",
+		Primary:    lipgloss.CompleteColor{TrueColor: "#6C71C4", ANSI256: "62", ANSI: "4"},   // Violet
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#2AA198", ANSI256: "36", ANSI: "6"},   // Cyan
+		Accent:     lipgloss.CompleteColor{TrueColor: "#859900", ANSI256: "100", ANSI: "3"},  // Green
+		Muted:      lipgloss.CompleteColor{TrueColor: "#93A1A1", ANSI256: "247", ANSI: "8"},  // Base1
+		Text:       lipgloss.CompleteColor{TrueColor: "#586E75", ANSI256: "242", ANSI: "0"},  // Base01
+		Warning:    lipgloss.CompleteColor{TrueColor: "#B58900", ANSI256: "136", ANSI: "3"},  // Yellow
+		Success:    lipgloss.CompleteColor{TrueColor: "#859900", ANSI256: "100", ANSI: "3"},  // Green
+		Error:      lipgloss.CompleteColor{TrueColor: "#DC322F", ANSI256: "160", ANSI: "1"},  // Red
+		Background: lipgloss.CompleteColor{TrueColor: "#FDF6E3", ANSI256: "230", ANSI: "15"}, // Base3
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#EEE8D5", ANSI256: "254", ANSI: "7"},  // Base2
+	},
+	"tokyonight-day": {
+		Name:       "Tokyo Night Day",
+		Primary:    lipgloss.CompleteColor{TrueColor: "#9854F1", ANSI256: "99", ANSI: "13"},  // Purple
+		Secondary:  lipgloss.CompleteColor{TrueColor: "#007197", ANSI256: "24", ANSI: "6"},   // Cyan
+		Accent:     lipgloss.CompleteColor{TrueColor: "#587539", ANSI256: "65", ANSI: "2"},   // Green
+		Muted:      lipgloss.CompleteColor{TrueColor: "#848CB5", ANSI256: "103", ANSI: "8"},  // Comment
+		Text:       lipgloss.CompleteColor{TrueColor: "#3760BF", ANSI256: "61", ANSI: "0"},   // Foreground
+		Warning:    lipgloss.CompleteColor{TrueColor: "#8C6C3E", ANSI256: "95", ANSI: "3"},   // Yellow
+		Success:    lipgloss.CompleteColor{TrueColor: "#587539", ANSI256: "65", ANSI: "2"},   // Green
+		Error:      lipgloss.CompleteColor{TrueColor: "#F52A65", ANSI256: "197", ANSI: "1"},  // Red
+		Background: lipgloss.CompleteColor{TrueColor: "#E1E2E7", ANSI256: "254", ANSI: "15"}, // BG
+		SelectedBg: lipgloss.CompleteColor{TrueColor: "#C4C8DA", ANSI256: "251", ANSI: "7"},  // BG highlight
 	},
 }
 
-// CurrentTheme holds the active theme
-var CurrentTheme = Themes["default"]
+// AutoTheme is the theme name that picks a light or dark theme to match the
+// terminal background
+const AutoTheme = "auto"
 
-// SetTheme changes the active theme and updates all styles
-func SetTheme(name string) bool {
-	theme, ok := Themes[name]
-	if !ok {
-		return false
+// lightVariants maps dark themes to their light counterparts
+var lightVariants = map[string]string{
+	"default":        "default-light",
+	"catppuccin":     "catppuccin-latte",
+	"gruvbox":        "gruvbox-light",
+	"solarized-dark": "solarized-light",
+	"tokyonight":     "tokyonight-day",
+}
+
+// ResolveTheme returns the key in Themes for a theme name. "auto" queries the
+// terminal background and picks the default dark or light theme; "<name>:auto"
+// does the same for a theme with a light variant, e.g. "gruvbox:auto".
+func ResolveTheme(name string) (string, bool) {
+	if name == AutoTheme {
+		name = "default:" + AutoTheme
 	}
-	CurrentTheme = theme
-	updateStyles()
-	return true
+	if base, ok := strings.CutSuffix(name, ":"+AutoTheme); ok {
+		name = resolveAutoTheme(base)
+	}
+
+	_, ok := Themes[name]
+	return name, ok
 }
 
-// GetThemeNames returns a list of available theme names
+// GetThemeNames returns a sorted list of available theme names
 func GetThemeNames() []string {
 	names := make([]string, 0, len(Themes))
 	for name := range Themes {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
 
-// updateStyles refreshes all styles with current theme colors
-func updateStyles() {
-	PrimaryColor = CurrentTheme.Primary
-	SecondaryColor = CurrentTheme.Secondary
-	MutedColor = CurrentTheme.Muted
-	TextColor = CurrentTheme.Text
-	WarningColor = CurrentTheme.Warning
-	SuccessColor = CurrentTheme.Success
-	SelectedBg = CurrentTheme.SelectedBg
-
-	// Update all styles
-	DateStyle = lipgloss.NewStyle().
-		Foreground(SecondaryColor).
-		Bold(true)
-
-	GreetingStyle = lipgloss.NewStyle().
-		Foreground(TextColor).
-		Italic(true)
-
-	SectionTitleStyle = lipgloss.NewStyle().
-		Foreground(PrimaryColor).
-		Bold(true).
-		MarginTop(1)
-
-	EventBoxStyle = lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(MutedColor).
-		Padding(0, 1)
-
-	EventTitleStyle = lipgloss.NewStyle().
-		Foreground(TextColor).
-		Bold(true)
-
-	EventTimeStyle = lipgloss.NewStyle().
-		Foreground(SecondaryColor)
-
-	EventAllDayStyle = lipgloss.NewStyle().
-		Foreground(WarningColor).
-		Italic(true)
-
-	NoEventsStyle = lipgloss.NewStyle().
-		Foreground(MutedColor).
-		Italic(true).
-		Padding(0, 1)
-
-	DividerStyle = lipgloss.NewStyle().
-		Foreground(MutedColor)
+// resolveAutoTheme returns base or its light variant to match the terminal
+func resolveAutoTheme(base string) string {
+	light, ok := lightVariants[base]
+	if !ok || lipgloss.HasDarkBackground() {
+		return base
+	}
+	return light
+}
 
-	CountdownStyle = lipgloss.NewStyle().
-		Foreground(WarningColor).
-		Bold(true)
+// IsLight reports whether the theme is designed for a light background
+func (t Theme) IsLight() bool {
+	hex := strings.TrimPrefix(t.Background.TrueColor, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return false
+	}
 
-	LabelStyle = lipgloss.NewStyle().
-		Foreground(MutedColor)
+	// Relative luminance approximation (ITU-R BT.601)
+	r, g, b := float64(rgb>>16&0xFF), float64(rgb>>8&0xFF), float64(rgb&0xFF)
+	return 0.299*r+0.587*g+0.114*b > 140
+}
 
-	SelectedStyle = lipgloss.NewStyle().
-		Background(SelectedBg).
-		Foreground(TextColor).
-		Bold(true).
-		Padding(0, 1)
+// IsBuiltIn reports whether the theme ships with myCal
+func (t Theme) IsBuiltIn() bool {
+	return t.File == ""
+}
 
-	HelpStyle = lipgloss.NewStyle().
-		Foreground(MutedColor).
-		MarginTop(1)
+// RenderSwatch renders a row of color blocks previewing the theme's palette
+func RenderSwatch(theme Theme) string {
+	colors := []lipgloss.CompleteColor{
+		theme.Primary,
+		theme.Secondary,
+		theme.Accent,
+		theme.Text,
+		theme.Muted,
+		theme.Warning,
+		theme.Success,
+		theme.Error,
+		theme.SelectedBg,
+	}
 
-	StatusStyle = lipgloss.NewStyle().
-		Foreground(SuccessColor).
-		Italic(true)
+	// Blocks sit on the theme's own background so light themes preview
+	// correctly in a dark terminal and vice versa
+	block := lipgloss.NewStyle().Background(theme.Background)
+	swatch := block.Render(" ")
+	for _, color := range colors {
+		swatch += block.Foreground(color).Render("██")
+	}
+	return swatch + block.Render(" ")
 }
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"oredavids.com/myCal/internal/calendar"
+)
+
+// Clock is a labelled time zone shown in the header's world clock
+type Clock struct {
+	Label    string
+	Location *time.Location
+}
+
+// SecondaryZones are shown beside each event time
+var SecondaryZones []*time.Location
+
+// WorldClock lists the zones shown in the header
+var WorldClock []Clock
+
+// SetTimeZones loads the secondary zones and world clock entries. A world
+// clock entry is an IANA zone name, optionally prefixed with a label, e.g.
+// "Ana=Europe/Lisbon"; without one the city is used.
+func SetTimeZones(secondary []string, clock []string) error {
+	SecondaryZones = nil
+	for _, name := range secondary {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return fmt.Errorf("unknown time zone %q", name)
+		}
+		SecondaryZones = append(SecondaryZones, loc)
+	}
+
+	WorldClock = nil
+	for _, entry := range clock {
+		label, name, found := strings.Cut(entry, "=")
+		if !found {
+			name = entry
+			label = zoneCity(entry)
+		}
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return fmt.Errorf("unknown time zone %q", name)
+		}
+		WorldClock = append(WorldClock, Clock{Label: label, Location: loc})
+	}
+	return nil
+}
+
+// zoneCity returns the city of an IANA zone name, e.g. "New York" for
+// "America/New_York"
+func zoneCity(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+// secondaryZoneText returns t in each secondary zone, e.g. " / 18:00 BST"
+func secondaryZoneText(t time.Time) string {
+	var b strings.Builder
+	for _, loc := range SecondaryZones {
+		b.WriteString(" / " + t.In(loc).Format("15:04 MST"))
+	}
+	return b.String()
+}
+
+// organizerZoneText notes the event's own zone when its clock differs from
+// ours, e.g. " · organizer 19:00 CEST"
+func organizerZoneText(event *calendar.Event) string {
+	loc := event.OrganizerZone()
+	if loc == nil {
+		return ""
+	}
+	return " · organizer " + event.StartTime.In(loc).Format("15:04 MST")
+}
+
+// worldClockText returns the current time in each world clock zone
+func worldClockText() string {
+	now := time.Now()
+	clocks := make([]string, 0, len(WorldClock))
+	for _, clock := range WorldClock {
+		clocks = append(clocks, clock.Label+" "+now.In(clock.Location).Format("15:04"))
+	}
+	return strings.Join(clocks, " · ")
+}